@@ -1,6 +1,7 @@
 package adapter
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,6 +18,9 @@ func NewOS() OS {
 
 func (O OS) ReadFile(name string) ([]byte, error) {
 	content, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, errors.NotFoundf("file %s not found", name)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "os.ReadFile")
 	}
@@ -32,6 +36,27 @@ func (O OS) AbsPath(baseDir, relPath string) (string, error) {
 	return filepath.Join(baseDir, relPath), nil
 }
 
+func (O OS) Glob(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, errors.Wrap(err, "filepath.Glob")
+	}
+
+	files := make([]string, 0, len(matches))
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			return nil, errors.Wrap(err, "os.Stat")
+		}
+
+		if !info.IsDir() {
+			files = append(files, match)
+		}
+	}
+
+	return files, nil
+}
+
 func (O OS) EnvVariables() map[string]string {
 	envMap := make(map[string]string)
 
@@ -1,19 +1,50 @@
 package analyzer
 
 import (
+	"sort"
+
 	ast2 "github.com/atmxlab/atmc/parser/ast"
 	"github.com/atmxlab/atmc/pkg/errors"
 )
 
 type Analyzer struct {
 	scope *scope
+	// Функции, которые можно вызывать из конфига.
+	functions map[string]struct{}
+	// Анализируемый файл и объекты, внутри которых находится текущий узел, - для проверки ссылок self и root.
+	file    ast2.File
+	objects []objectFrame
 }
 
-func New() *Analyzer {
-	return &Analyzer{scope: newScope()}
+type Option func(*Analyzer)
+
+// WithFunctions задает имена функций, которые можно вызывать из конфига.
+func WithFunctions(names ...string) Option {
+	return func(ar *Analyzer) {
+		for _, name := range names {
+			ar.functions[name] = struct{}{}
+		}
+	}
+}
+
+func New(opts ...Option) *Analyzer {
+	ar := &Analyzer{
+		scope:     newScope(),
+		functions: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(ar)
+	}
+
+	return ar
 }
 
 func (ar *Analyzer) Analyze(a ast2.Ast) error {
+	// Переменные у каждого файла свои.
+	ar.scope = newScope()
+	ar.file = a.Root()
+	ar.objects = nil
+
 	err := a.Inspect(ar.Visit)
 	if err != nil {
 		return errors.Wrap(err, "inspect")
@@ -30,9 +61,14 @@ func (ar *Analyzer) Analyze(a ast2.Ast) error {
 func (ar *Analyzer) Visit(node ast2.Node) error {
 	switch n := node.(type) {
 	case ast2.File:
+		// Импорты и локальные переменные регистрируются заранее, чтобы они могли ссылаться друг на друга в любом порядке.
+		if err := ar.addDeclarations(n); err != nil {
+			return errors.Wrap(err, "add declarations")
+		}
 	case ast2.Import:
-		ar.scope.addVariable(n.Name().String())
+	case ast2.Definition:
 	case ast2.Object:
+		ar.enterObject(n)
 	case ast2.Spread:
 	case ast2.KV:
 	case ast2.Array:
@@ -44,8 +80,26 @@ func (ar *Analyzer) Visit(node ast2.Node) error {
 	case ast2.Env:
 	case ast2.Int:
 	case ast2.Float:
+	case ast2.Duration:
+	case ast2.ByteSize:
 	case ast2.String:
+	case ast2.Template:
+	case ast2.Binary:
+	case ast2.Unary:
+	case ast2.Conditional:
+	case ast2.Call:
+		if _, ok := ar.functions[n.Name().String()]; !ok {
+			return errors.Wrapf(
+				ErrUnknownFunction,
+				"%s at %d:%d",
+				n.Name().String(),
+				n.Location().Start().Line(),
+				n.Location().Start().Column(),
+			)
+		}
 	case ast2.Bool:
+	case ast2.Null:
+	case ast2.Delete:
 	default:
 		return errors.New("invalid node type")
 	}
@@ -53,12 +107,58 @@ func (ar *Analyzer) Visit(node ast2.Node) error {
 	return nil
 }
 
+func (ar *Analyzer) addDeclarations(f ast2.File) error {
+	names := make([]ast2.Ident, 0, len(f.Imports())+len(f.Definitions()))
+	for _, imp := range f.Imports() {
+		names = append(names, imp.Name())
+	}
+	for _, def := range f.Definitions() {
+		names = append(names, def.Name())
+	}
+
+	// Повторным считается объявление, которое стоит в файле позже.
+	sort.SliceStable(names, func(i, j int) bool {
+		a, b := names[i].Location().Start(), names[j].Location().Start()
+		return a.Line() < b.Line() || a.Line() == b.Line() && a.Column() < b.Column()
+	})
+
+	for _, name := range names {
+		if isSelfVariable(name.String()) {
+			return errors.Wrapf(
+				ErrReservedVariable,
+				"%s at %d:%d",
+				name.String(),
+				name.Location().Start().Line(),
+				name.Location().Start().Column(),
+			)
+		}
+
+		if ar.scope.hasVariable(name.String()) {
+			return errors.Wrapf(
+				ErrDuplicateVariable,
+				"%s at %d:%d",
+				name.String(),
+				name.Location().Start().Line(),
+				name.Location().Start().Column(),
+			)
+		}
+
+		ar.scope.addVariable(name.String())
+	}
+
+	return nil
+}
+
 func (ar *Analyzer) checkVar(v ast2.Var) error {
 	if len(v.Path()) == 0 {
 		return errors.Newf("invalid variable. variable path is empty")
 	}
 	firstPartFromVarPath := v.Path()[0]
 
+	if isSelfVariable(firstPartFromVarPath.String()) {
+		return ar.checkSelfVar(v)
+	}
+
 	if !ar.scope.hasVariable(firstPartFromVarPath.String()) {
 		return errors.Wrapf(ErrUndefinedVariable, "undefined variable: %s", firstPartFromVarPath.String())
 	}
@@ -4,7 +4,10 @@ import (
 	"testing"
 
 	"github.com/atmxlab/atmc/analyzer"
+	"github.com/atmxlab/atmc/parser/ast"
 	testast2 "github.com/atmxlab/atmc/test/testast"
+	"github.com/atmxlab/atmc/types"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
 )
 
@@ -67,4 +70,183 @@ func TestAnalyzer_Analyze(t *testing.T) {
 		err := a.Analyze(b.Build())
 		require.ErrorIs(t, err, analyzer.ErrUndefinedVariable)
 	})
+
+	t.Run("unknown_function", func(t *testing.T) {
+		t.Parallel()
+
+		b := testast2.NewAstBuilder()
+
+		b.Object(func(ob *testast2.ObjectBuilder) {
+			ob.KV(func(kb *testast2.KVBuilder) {
+				kb.
+					Key(testast2.NewIdent("key1")).
+					Value(ast.NewCall(testast2.NewIdent("secret"), []ast.Expression{}, types.Location{}))
+			})
+		})
+
+		a := analyzer.New(analyzer.WithFunctions("upper"))
+
+		err := a.Analyze(b.Build())
+		require.ErrorIs(t, err, analyzer.ErrUnknownFunction)
+	})
+
+	t.Run("known_function", func(t *testing.T) {
+		t.Parallel()
+
+		b := testast2.NewAstBuilder()
+
+		b.Object(func(ob *testast2.ObjectBuilder) {
+			ob.KV(func(kb *testast2.KVBuilder) {
+				kb.
+					Key(testast2.NewIdent("key1")).
+					Value(ast.NewCall(testast2.NewIdent("secret"), []ast.Expression{}, types.Location{}))
+			})
+		})
+
+		a := analyzer.New(analyzer.WithFunctions("upper", "secret"))
+
+		err := a.Analyze(b.Build())
+		require.NoError(t, err)
+	})
+
+	t.Run("definitions", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			name        string
+			definitions []ast.Definition
+			value       ast.Expression
+			expected    error
+		}{
+			{
+				name: "used_in_any_order",
+				definitions: []ast.Definition{
+					ast.NewDefinition(testast2.NewIdent("url"), newVar("host")),
+					ast.NewDefinition(testast2.NewIdent("host"), ast.NewString("db.internal", types.Location{})),
+				},
+				value: newVar("url"),
+			},
+			{
+				name: "unused",
+				definitions: []ast.Definition{
+					ast.NewDefinition(testast2.NewIdent("host"), ast.NewString("db.internal", types.Location{})),
+				},
+				value:    ast.NewString("x", types.Location{}),
+				expected: analyzer.ErrUnusedVariable,
+			},
+			{
+				name: "undefined",
+				definitions: []ast.Definition{
+					ast.NewDefinition(testast2.NewIdent("url"), newVar("host")),
+				},
+				value:    newVar("url"),
+				expected: analyzer.ErrUndefinedVariable,
+			},
+			{
+				name: "duplicate",
+				definitions: []ast.Definition{
+					ast.NewDefinition(testast2.NewIdent("host"), ast.NewString("a", types.Location{})),
+					ast.NewDefinition(testast2.NewIdent("host"), ast.NewString("b", types.Location{})),
+				},
+				value:    newVar("host"),
+				expected: analyzer.ErrDuplicateVariable,
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				b := testast2.NewAstBuilder()
+
+				b.Object(func(ob *testast2.ObjectBuilder) {
+					ob.KV(func(kb *testast2.KVBuilder) {
+						kb.
+							Key(testast2.NewIdent("key1")).
+							Value(tc.value)
+					})
+				})
+
+				file := b.Build().Root().SetDefinitions(tc.definitions)
+
+				err := analyzer.New().Analyze(ast.NewAst(file))
+				if tc.expected == nil {
+					require.NoError(t, err)
+					return
+				}
+
+				require.ErrorIs(t, err, tc.expected)
+			})
+		}
+	})
+
+	t.Run("self_references", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			name        string
+			definitions []ast.Definition
+			value       ast.Expression
+			expected    error
+		}{
+			{
+				name:  "sibling_key",
+				value: newVar("self", "host"),
+			},
+			{
+				name:  "root_key",
+				value: newVar("root", "host"),
+			},
+			{
+				name:     "undefined_key",
+				value:    newVar("self", "port"),
+				expected: analyzer.ErrUndefinedKey,
+			},
+			{
+				name: "reserved_name",
+				definitions: []ast.Definition{
+					ast.NewDefinition(testast2.NewIdent("root"), ast.NewString("x", types.Location{})),
+				},
+				value:    newVar("root", "host"),
+				expected: analyzer.ErrReservedVariable,
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				b := testast2.NewAstBuilder()
+
+				b.Object(func(ob *testast2.ObjectBuilder) {
+					ob.KV(func(kb *testast2.KVBuilder) {
+						kb.
+							Key(testast2.NewIdent("host")).
+							Value(ast.NewString("localhost", types.Location{}))
+					})
+					ob.KV(func(kb *testast2.KVBuilder) {
+						kb.
+							Key(testast2.NewIdent("url")).
+							Value(tc.value)
+					})
+				})
+
+				file := b.Build().Root().SetDefinitions(tc.definitions)
+
+				err := analyzer.New().Analyze(ast.NewAst(file))
+				if tc.expected == nil {
+					require.NoError(t, err)
+					return
+				}
+
+				require.ErrorIs(t, err, tc.expected)
+			})
+		}
+	})
+}
+
+func newVar(path ...string) ast.Var {
+	return ast.NewVar(lo.Map(path, func(item string, _ int) ast.Ident {
+		return testast2.NewIdent(item)
+	}))
 }
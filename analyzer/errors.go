@@ -5,4 +5,9 @@ import "github.com/atmxlab/atmc/pkg/errors"
 var (
 	ErrUnusedVariable    = errors.New("unused variable")
 	ErrUndefinedVariable = errors.New("undefined variable")
+	ErrUnknownFunction   = errors.New("unknown function")
+	ErrDuplicateVariable = errors.New("duplicate variable")
+	ErrReservedVariable  = errors.New("reserved variable name")
+	ErrUndefinedKey      = errors.New("undefined key")
+	ErrIndexOutOfRange   = errors.New("index out of range")
 )
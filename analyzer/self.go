@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	ast2 "github.com/atmxlab/atmc/parser/ast"
+	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/atmxlab/atmc/types"
+	"github.com/samber/lo"
+)
+
+const (
+	rootVariable = "root"
+	selfVariable = "self"
+)
+
+func isSelfVariable(name string) bool {
+	return name == rootVariable || name == selfVariable
+}
+
+// objectFrame литерал объекта, внутри которого находится текущий узел.
+type objectFrame struct {
+	object ast2.Object
+	// Путь от корня документа, если до объекта можно дойти только по ключам.
+	// Такой объект может слиться с объектами под тем же ключом, поэтому ключи ищутся от корня.
+	keyPath  []string
+	fromRoot bool
+}
+
+func contains(loc types.Location, pos types.Position) bool {
+	return loc.Start().Pos() <= pos.Pos() && pos.Pos() <= loc.End().Pos()
+}
+
+// leaveObjects убирает объекты, которые уже пройдены: узлы обходятся в прямом порядке.
+func (ar *Analyzer) leaveObjects(pos types.Position) {
+	for len(ar.objects) > 0 && !contains(ar.objects[len(ar.objects)-1].object.Location(), pos) {
+		ar.objects = ar.objects[:len(ar.objects)-1]
+	}
+}
+
+func (ar *Analyzer) enterObject(obj ast2.Object) {
+	ar.leaveObjects(obj.Location().Start())
+
+	frame := objectFrame{object: obj}
+
+	if len(ar.objects) == 0 {
+		frame.fromRoot = obj.Location() == ar.file.Value().Location()
+	} else if parent := ar.objects[len(ar.objects)-1]; parent.fromRoot {
+		for _, entry := range parent.object.Entries() {
+			kv, ok := entry.(ast2.KV)
+			if !ok {
+				continue
+			}
+
+			if value, ok := kv.Value().(ast2.Object); ok && value.Location() == obj.Location() {
+				frame.fromRoot = true
+				frame.keyPath = append(append([]string{}, parent.keyPath...), kv.Key().String())
+
+				break
+			}
+		}
+	}
+
+	ar.objects = append(ar.objects, frame)
+}
+
+// checkSelfVar проверяет, что ключи и индексы, на которые ссылаются self и root, есть в документе.
+// Проверка идет по литералам: если значение вычисляется или в объекте есть spread, она останавливается.
+func (ar *Analyzer) checkSelfVar(v ast2.Var) error {
+	values := []ast2.Expression{ar.file.Value()}
+	path := make([]ast2.Ident, 0, len(v.Path()))
+
+	if v.Path()[0].String() == selfVariable {
+		ar.leaveObjects(v.Location().Start())
+
+		if len(ar.objects) == 0 {
+			return errors.Wrapf(
+				ErrUndefinedVariable,
+				"%s is used outside of an object at %d:%d",
+				selfVariable,
+				v.Location().Start().Line(),
+				v.Location().Start().Column(),
+			)
+		}
+
+		frame := ar.objects[len(ar.objects)-1]
+		if frame.fromRoot {
+			for _, key := range frame.keyPath {
+				path = append(path, ast2.NewIdent(key, types.Location{}))
+			}
+		} else {
+			values = []ast2.Expression{frame.object}
+		}
+	}
+
+	path = append(path, v.Path()[1:]...)
+
+	for _, segment := range path {
+		if index, ok := segment.(ast2.Index); ok {
+			// Одинаковые ключи могут сливаться по разным стратегиям - проверяется только единственный массив.
+			if len(values) != 1 {
+				return nil
+			}
+
+			arr, ok := values[0].(ast2.Array)
+			if !ok || lo.ContainsBy(arr.Elements(), isSpread) {
+				return nil
+			}
+
+			i := index.Index()
+			if i < 0 {
+				i += len(arr.Elements())
+			}
+
+			if i < 0 || i >= len(arr.Elements()) {
+				return errors.Wrapf(
+					ErrIndexOutOfRange,
+					"%s: index %d out of range for array of length %d at %d:%d",
+					v.String(),
+					index.Index(),
+					len(arr.Elements()),
+					index.Location().Start().Line(),
+					index.Location().Start().Column(),
+				)
+			}
+
+			values = []ast2.Expression{arr.Elements()[i]}
+
+			continue
+		}
+
+		next := make([]ast2.Expression, 0)
+
+		for _, value := range values {
+			obj, ok := value.(ast2.Object)
+			if !ok {
+				// Значение вычисляется при линковке.
+				return nil
+			}
+
+			for _, entry := range obj.Entries() {
+				switch e := entry.(type) {
+				case ast2.KV:
+					if e.Key().String() == segment.String() {
+						next = append(next, e.Value())
+					}
+				default:
+					// Ключи из spread известны только при линковке.
+					return nil
+				}
+			}
+		}
+
+		if len(next) == 0 {
+			return errors.Wrapf(
+				ErrUndefinedKey,
+				"%s at %d:%d",
+				v.String(),
+				v.Location().Start().Line(),
+				v.Location().Start().Column(),
+			)
+		}
+
+		values = next
+	}
+
+	return nil
+}
+
+func isSpread(exp ast2.Expression) bool {
+	_, ok := exp.(ast2.Spread)
+	return ok
+}
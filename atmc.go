@@ -2,6 +2,7 @@ package atmc
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/atmxlab/atmc/adapter"
 	"github.com/atmxlab/atmc/analyzer"
@@ -12,6 +13,7 @@ import (
 	"github.com/atmxlab/atmc/parser"
 	"github.com/atmxlab/atmc/pkg/errors"
 	"github.com/atmxlab/atmc/processor"
+	"github.com/atmxlab/atmc/types/token"
 )
 
 const (
@@ -19,7 +21,10 @@ const (
 )
 
 type config struct {
-	fieldTag string
+	fieldTag  string
+	functions []linker.Option
+	// Ошибки опций - их возвращает Load.
+	errs []error
 }
 
 type option func(*config)
@@ -30,6 +35,41 @@ func WithFieldTag(tag string) option {
 	}
 }
 
+// WithFunction регистрирует Go-функцию, которую можно вызывать из конфига: secret("db/password").
+// Функция возвращает значение или значение и ошибку, аргументы и результат - string, bool, числа,
+// time.Duration, слайсы, map[string]T, указатели и any.
+// Если имя не является идентификатором, совпадает со встроенной функцией или сигнатура не поддерживается,
+// функция не регистрируется, а Load и JSON возвращают ошибку.
+func WithFunction(name string, fn any) option {
+	return func(c *config) {
+		if err := checkFunctionName(name); err != nil {
+			c.errs = append(c.errs, errors.Wrapf(err, "register function %s", name))
+			return
+		}
+
+		function, err := linker.NewFunction(fn)
+		if err != nil {
+			c.errs = append(c.errs, errors.Wrapf(err, "register function %s", name))
+			return
+		}
+
+		c.functions = append(c.functions, linker.WithFunction(name, function))
+	}
+}
+
+func checkFunctionName(name string) error {
+	tokens, err := lexer.New().Tokenize(name)
+	if err != nil || len(tokens) != 1 || tokens[0].Type() != token.Ident || tokens[0].Value().String() != name {
+		return errors.New("invalid function name")
+	}
+
+	if linker.IsBuiltinFunction(name) {
+		return errors.New("function name is reserved by builtin function")
+	}
+
+	return nil
+}
+
 type ATMC struct {
 	processor *processor.Processor
 	config    config
@@ -42,12 +82,14 @@ func New(opts ...option) *ATMC {
 	for _, opt := range opts {
 		opt(&cfg)
 	}
+	lnk := linker.New(cfg.functions...)
+
 	return &ATMC{
 		processor: processor.New(
 			lexer.New(),
 			parser.New(),
-			analyzer.New(),
-			linker.New(),
+			analyzer.New(analyzer.WithFunctions(lnk.FunctionNames()...)),
+			lnk,
 			adapter.NewOS(),
 		),
 		config: cfg,
@@ -55,6 +97,10 @@ func New(opts ...option) *ATMC {
 }
 
 func (c *ATMC) Load(path string) (*Scanner, error) {
+	if err := errors.Join(c.config.errs...); err != nil {
+		return nil, errors.Wrap(err, "options")
+	}
+
 	a, err := c.processor.Process(path)
 	if err != nil {
 		return nil, errors.Wrap(err, "processor.Process")
@@ -77,15 +123,40 @@ func (c *ATMC) JSON(path string) ([]byte, error) {
 		return nil, errors.Wrap(err, "load")
 	}
 
-	m := make(map[string]any)
-	if err = scanner.Scan(m); err != nil {
+	// Корень документа может быть не объектом: массивом или скаляром.
+	var v any
+	if err = scanner.Scan(&v); err != nil {
 		return nil, errors.Wrap(err, "scanner.Scan")
 	}
 
-	bytes, err := json.Marshal(m)
+	bytes, err := json.Marshal(jsonValue(v))
 	if err != nil {
 		return nil, errors.Wrap(err, "json.Marshal")
 	}
 
 	return bytes, nil
 }
+
+// jsonValue приводит скомпилированные значения к JSON-представлению:
+// длительности записываются строкой в формате time.Duration.String ("1m30s"),
+// размеры - целым количеством байт.
+func jsonValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, elem := range val {
+			val[k] = jsonValue(elem)
+		}
+
+		return val
+	case []any:
+		for i, elem := range val {
+			val[i] = jsonValue(elem)
+		}
+
+		return val
+	case time.Duration:
+		return val.String()
+	default:
+		return val
+	}
+}
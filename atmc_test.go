@@ -0,0 +1,134 @@
+package atmc_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/atmxlab/atmc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestATMC_JSON(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.atmc")
+	err := os.WriteFile(path, []byte(`{
+	timeout: 1m30s
+	retries: [250ms 2s]
+	buffer: 512MiB
+}`), 0o600)
+	require.NoError(t, err)
+
+	bytes, err := atmc.New().JSON(path)
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{
+	"timeout": "1m30s",
+	"retries": ["250ms", "2s"],
+	"buffer": 536870912
+}`, string(bytes))
+}
+
+func TestATMC_JSON_NonObjectRoot(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "levels.yml"), []byte("- warn\n- error\n"), 0o600)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, "levels.atmc"), []byte(`
+levels ./levels.yml
+
+levels
+`), 0o600)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, "timeout.atmc"), []byte(`1m30s`), 0o600)
+	require.NoError(t, err)
+
+	bytes, err := atmc.New().JSON(filepath.Join(dir, "levels.atmc"))
+	require.NoError(t, err)
+	require.JSONEq(t, `["warn", "error"]`, string(bytes))
+
+	bytes, err = atmc.New().JSON(filepath.Join(dir, "timeout.atmc"))
+	require.NoError(t, err)
+	require.JSONEq(t, `"1m30s"`, string(bytes))
+}
+
+func TestATMC_WithFunction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("call", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "config.atmc")
+		err := os.WriteFile(path, []byte(`{
+	endpoint: region_endpoint("eu")
+	password: secret("db/password")
+}`), 0o600)
+		require.NoError(t, err)
+
+		secrets := map[string]string{"db/password": "qwerty"}
+
+		c := atmc.New(
+			atmc.WithFunction("region_endpoint", func(region string) string {
+				return "https://" + region + ".example.com"
+			}),
+			atmc.WithFunction("secret", func(key string) (string, error) {
+				value, ok := secrets[key]
+				if !ok {
+					return "", errors.New("secret not found")
+				}
+
+				return value, nil
+			}),
+		)
+
+		bytes, err := c.JSON(path)
+		require.NoError(t, err)
+
+		require.JSONEq(t, `{
+	"endpoint": "https://eu.example.com",
+	"password": "qwerty"
+}`, string(bytes))
+	})
+
+	t.Run("invalid registration", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "config.atmc")
+		err := os.WriteFile(path, []byte(`{name: title("api")}`), 0o600)
+		require.NoError(t, err)
+
+		testCases := []struct {
+			name    string
+			fn      any
+			message string
+		}{
+			{name: "upper", fn: strings.ToUpper, message: "function name is reserved by builtin function"},
+			{name: "if", fn: strings.ToUpper, message: "invalid function name"},
+			{name: "my-func", fn: strings.ToUpper, message: "invalid function name"},
+			{name: "fn", fn: "not a function", message: "expected function, got string"},
+			{name: "fn", fn: func() {}, message: "expected function returning value or value and error"},
+			{name: "fn", fn: func(ch chan int) int { return 0 }, message: "unsupported argument 1 type chan int"},
+		}
+
+		for _, tc := range testCases {
+			c := atmc.New(
+				atmc.WithFunction("title", strings.ToTitle),
+				atmc.WithFunction(tc.name, tc.fn),
+			)
+
+			_, err = c.JSON(path)
+			require.ErrorContains(t, err, "register function "+tc.name+": "+tc.message)
+		}
+
+		bytes, err := atmc.New(atmc.WithFunction("title", strings.ToTitle)).JSON(path)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name": "API"}`, string(bytes))
+	})
+}
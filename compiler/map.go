@@ -13,7 +13,12 @@ func NewMapCompiler() *MapCompiler {
 }
 
 func (c *MapCompiler) Compile(t map[string]any, a ast.Ast) error {
-	compiled, err := c.compileObj(a.Object())
+	obj, ok := a.Root().(ast.Object)
+	if !ok {
+		return errors.Wrapf(ErrInvalidType, "expected object as document root, got %s", kindName(a.Root()))
+	}
+
+	compiled, err := c.compileObj(obj)
 	if err != nil {
 		return err
 	}
@@ -23,6 +28,35 @@ func (c *MapCompiler) Compile(t map[string]any, a ast.Ast) error {
 	return nil
 }
 
+// CompileSlice компилирует документ, корень которого - массив.
+func (c *MapCompiler) CompileSlice(t *[]any, a ast.Ast) error {
+	arr, ok := a.Root().(ast.Array)
+	if !ok {
+		return errors.Wrapf(ErrInvalidType, "expected array as document root, got %s", kindName(a.Root()))
+	}
+
+	compiled, err := c.compileArr(arr)
+	if err != nil {
+		return err
+	}
+
+	*t = compiled
+
+	return nil
+}
+
+// CompileValue компилирует документ с корнем любого вида: объект, массив или скаляр.
+func (c *MapCompiler) CompileValue(t *any, a ast.Ast) error {
+	compiled, err := c.compileExpr(a.Root())
+	if err != nil {
+		return err
+	}
+
+	*t = compiled
+
+	return nil
+}
+
 func (c *MapCompiler) compileObj(obj ast.Object) (map[string]any, error) {
 	m := make(map[string]any)
 	for _, kv := range obj.KV() {
@@ -64,7 +98,39 @@ func (c *MapCompiler) compileExpr(exp ast.Expression) (any, error) {
 		return v.Value(), nil
 	case ast.Float:
 		return v.Value(), nil
+	case ast.Duration:
+		return v.Value(), nil
+	case ast.ByteSize:
+		return v.Value(), nil
+	case ast.Null:
+		return nil, nil
 	default:
 		return nil, errors.New("unexpected expression type")
 	}
 }
+
+// kindName название типа значения для сообщений об ошибках.
+func kindName(exp ast.Expression) string {
+	switch exp.(type) {
+	case ast.Object:
+		return "object"
+	case ast.Array:
+		return "array"
+	case ast.String:
+		return "string"
+	case ast.Bool:
+		return "bool"
+	case ast.Int:
+		return "int"
+	case ast.Float:
+		return "float"
+	case ast.Duration:
+		return "duration"
+	case ast.ByteSize:
+		return "byte size"
+	case ast.Null:
+		return "null"
+	default:
+		return "unknown"
+	}
+}
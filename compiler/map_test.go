@@ -2,6 +2,7 @@ package compiler_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/atmxlab/atmc/compiler"
 	linkedast "github.com/atmxlab/atmc/linker/ast"
@@ -53,4 +54,91 @@ func TestMapCompiler(t *testing.T) {
 		require.NoError(t, err)
 		testutils.AssertEmptyDiff(t, expectedMap, actualMap)
 	})
+	t.Run("with_null", func(t *testing.T) {
+		t.Parallel()
+
+		a := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.KV2("a", linkedast.NewNull())
+				ob.KV2("b", testlinkedast.NewArrayBuilder().
+					Element(linkedast.NewNull()).
+					Element(linkedast.NewInt(1)).
+					Build(),
+				)
+			}).
+			Build()
+
+		expectedMap := map[string]any{
+			"a": nil,
+			"b": []any{nil, int64(1)},
+		}
+
+		mc := compiler.NewMapCompiler()
+
+		actualMap := make(map[string]any)
+		err := mc.Compile(actualMap, a)
+		require.NoError(t, err)
+		testutils.AssertEmptyDiff(t, expectedMap, actualMap)
+	})
+	t.Run("with_duration_and_byte_size", func(t *testing.T) {
+		t.Parallel()
+
+		a := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.KV2("timeout", linkedast.NewDuration(90*time.Second))
+				ob.KV2("buffer", linkedast.NewByteSize(512<<20))
+			}).
+			Build()
+
+		expectedMap := map[string]any{
+			"timeout": 90 * time.Second,
+			"buffer":  uint64(512 << 20),
+		}
+
+		mc := compiler.NewMapCompiler()
+
+		actualMap := make(map[string]any)
+		err := mc.Compile(actualMap, a)
+		require.NoError(t, err)
+		testutils.AssertEmptyDiff(t, expectedMap, actualMap)
+	})
+	t.Run("with_array_root", func(t *testing.T) {
+		t.Parallel()
+
+		a := testlinkedast.NewBuilder().
+			Root(testlinkedast.NewArrayBuilder().
+				Element(linkedast.NewString("warn")).
+				Element(testlinkedast.NewObjectBuilder().KV2("level", linkedast.NewString("error")).Build()).
+				Build(),
+			).
+			Build()
+
+		mc := compiler.NewMapCompiler()
+
+		err := mc.Compile(make(map[string]any), a)
+		require.ErrorIs(t, err, compiler.ErrInvalidType)
+		require.ErrorContains(t, err, "expected object as document root, got array")
+
+		var actual []any
+		err = mc.CompileSlice(&actual, a)
+		require.NoError(t, err)
+		testutils.AssertEmptyDiff(t, []any{"warn", map[string]any{"level": "error"}}, actual)
+
+		var value any
+		err = mc.CompileValue(&value, a)
+		require.NoError(t, err)
+		testutils.AssertEmptyDiff[any](t, []any{"warn", map[string]any{"level": "error"}}, value)
+	})
+	t.Run("with_scalar_root", func(t *testing.T) {
+		t.Parallel()
+
+		a := testlinkedast.NewBuilder().
+			Root(linkedast.NewInt(8080)).
+			Build()
+
+		var value any
+		err := compiler.NewMapCompiler().CompileValue(&value, a)
+		require.NoError(t, err)
+		require.Equal(t, int64(8080), value)
+	})
 }
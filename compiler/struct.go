@@ -3,11 +3,14 @@ package compiler
 import (
 	"math"
 	"reflect"
+	"time"
 
 	"github.com/atmxlab/atmc/linker/ast"
 	"github.com/atmxlab/atmc/pkg/errors"
 )
 
+var durationType = reflect.TypeOf(time.Duration(0))
+
 type StructCompiler struct {
 	tagName string
 }
@@ -16,9 +19,38 @@ func NewStructCompiler(tagName string) *StructCompiler {
 	return &StructCompiler{tagName: tagName}
 }
 
-func (c *StructCompiler) Compile(t any, ast ast.Ast) error {
-	if err := c.processObject(ast.Object(), reflect.ValueOf(t)); err != nil {
-		return errors.Wrap(err, "processObject")
+// Compile заполняет t значением документа. Объект декодируется в указатель на структуру,
+// массив - в указатель на слайс, скаляр - в указатель на значение подходящего типа.
+func (c *StructCompiler) Compile(t any, a ast.Ast) error {
+	v := reflect.ValueOf(t)
+
+	if _, ok := a.Root().(ast.Object); !ok && (v.Kind() != reflect.Ptr || v.IsNil()) {
+		return errors.Wrapf(ErrInvalidType, "expected pointer for %s document root, got [%s]", kindName(a.Root()), v.Kind())
+	}
+
+	switch root := a.Root().(type) {
+	case ast.Object:
+		if err := c.processObject(root, v); err != nil {
+			return errors.Wrap(err, "processObject")
+		}
+	case ast.Array:
+		if v.Elem().Kind() != reflect.Slice {
+			return errors.Wrapf(ErrInvalidType, "expected slice for array document root, got [%s]", v.Elem().Kind())
+		}
+
+		if err := c.processArray(root, v); err != nil {
+			return errors.Wrap(err, "processArray")
+		}
+	case ast.Int, ast.Float, ast.Duration, ast.ByteSize, ast.String, ast.Bool:
+		if v.Elem().Kind() == reflect.Struct || v.Elem().Kind() == reflect.Slice {
+			return errors.Wrapf(ErrInvalidType, "expected %s, got [%s]", kindName(root), v.Elem().Kind())
+		}
+
+		if err := c.processLiteral(root, c.makeValueRecursive(v)); err != nil {
+			return errors.Wrap(err, "processLiteral")
+		}
+	case ast.Null:
+		v.Elem().Set(reflect.Zero(v.Elem().Type()))
 	}
 
 	return nil
@@ -53,12 +85,15 @@ func (c *StructCompiler) processObject(obj ast.Object, v reflect.Value) error {
 			if err = c.processObject(astV, object.Addr()); err != nil {
 				return errors.Wrap(err, "c.processObject")
 			}
-		case ast.Int, ast.Float, ast.String, ast.Bool:
+		case ast.Int, ast.Float, ast.Duration, ast.ByteSize, ast.String, ast.Bool:
 			literal := c.makeValueRecursive(field)
 
 			if err = c.processLiteral(astV, literal); err != nil {
 				return errors.Wrap(err, "c.processLiteral")
 			}
+		case ast.Null:
+			// null сбрасывает поле в zero value: nil для указателей, слайсов и map.
+			field.Elem().Set(reflect.Zero(field.Elem().Type()))
 		}
 	}
 
@@ -82,10 +117,12 @@ func (c *StructCompiler) processArray(arr ast.Array, field reflect.Value) error
 			if err := c.processArray(astV, elem); err != nil {
 				return errors.Wrap(err, "processArray")
 			}
-		case ast.Int, ast.Float, ast.String, ast.Bool:
+		case ast.Int, ast.Float, ast.Duration, ast.ByteSize, ast.String, ast.Bool:
 			if err := c.processLiteral(astV, elem); err != nil {
 				return errors.Wrap(err, "c.processLiteral")
 			}
+		case ast.Null:
+			// Элемент уже содержит zero value.
 		}
 	}
 
@@ -102,13 +139,17 @@ func (c *StructCompiler) processLiteral(
 	case ast.Int:
 		switch field.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			if err := c.checkInt(astV, field.Kind()); err != nil {
+			if err := c.checkInt(astV.Value(), field.Kind()); err != nil {
 				return errors.Wrap(err, "c.checkInt")
 			}
 
 			field.SetInt(astV.Value())
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-			if err := c.checkUInt(astV, field.Kind()); err != nil {
+			if astV.Value() < 0 {
+				return errors.Wrapf(ErrTypeOverflow, "unsigned integer value overflow: actual value: [%d], min value: [0], kind: [%s]", astV.Value(), field.Kind())
+			}
+
+			if err := c.checkUInt(uint64(astV.Value()), field.Kind()); err != nil {
 				return errors.Wrap(err, "c.checkUInt")
 			}
 
@@ -116,6 +157,34 @@ func (c *StructCompiler) processLiteral(
 		default:
 			return errors.Wrapf(ErrInvalidType, "expected int, got [%s]", field.Kind())
 		}
+	case ast.Duration:
+		if field.Type() != durationType {
+			return errors.Wrapf(ErrInvalidType, "expected %s, got [%s]", durationType, field.Type())
+		}
+
+		field.SetInt(int64(astV.Value()))
+	case ast.ByteSize:
+		// Размер записывается в любое целочисленное поле как количество байт.
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if astV.Value() > math.MaxInt64 {
+				return errors.Wrapf(ErrTypeOverflow, "integer value overflow: actual value: [%d], max value: [%d], kind: [%s]", astV.Value(), int64(math.MaxInt64), field.Kind())
+			}
+
+			if err := c.checkInt(int64(astV.Value()), field.Kind()); err != nil {
+				return errors.Wrap(err, "c.checkInt")
+			}
+
+			field.SetInt(int64(astV.Value()))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if err := c.checkUInt(astV.Value(), field.Kind()); err != nil {
+				return errors.Wrap(err, "c.checkUInt")
+			}
+
+			field.SetUint(astV.Value())
+		default:
+			return errors.Wrapf(ErrInvalidType, "expected int, got [%s]", field.Kind())
+		}
 	case ast.Float:
 		if err := c.checkFloat(astV, field.Kind()); err != nil {
 			return errors.Wrap(err, "c.checkFloat")
@@ -131,7 +200,7 @@ func (c *StructCompiler) processLiteral(
 	return nil
 }
 
-func (c *StructCompiler) checkInt(astV ast.Int, kind reflect.Kind) error {
+func (c *StructCompiler) checkInt(value int64, kind reflect.Kind) error {
 	var minV, maxV int64
 
 	switch kind {
@@ -154,19 +223,19 @@ func (c *StructCompiler) checkInt(astV ast.Int, kind reflect.Kind) error {
 		return errors.Wrapf(ErrInvalidType, "expected int, got [%s]", kind)
 	}
 
-	if astV.Value() > maxV {
-		return errors.Wrapf(ErrTypeOverflow, "integer value overflow: actual value: [%d], max value: [%d], kind: [%s]", astV.Value(), maxV, kind)
+	if value > maxV {
+		return errors.Wrapf(ErrTypeOverflow, "integer value overflow: actual value: [%d], max value: [%d], kind: [%s]", value, maxV, kind)
 	}
 
-	if astV.Value() < minV {
-		return errors.Wrapf(ErrTypeOverflow, "integer value overflow: actual value: [%d], min value: [%d], kind: [%s]", astV.Value(), minV, kind)
+	if value < minV {
+		return errors.Wrapf(ErrTypeOverflow, "integer value overflow: actual value: [%d], min value: [%d], kind: [%s]", value, minV, kind)
 	}
 
 	return nil
 }
 
-func (c *StructCompiler) checkUInt(astV ast.Int, kind reflect.Kind) error {
-	var minV, maxV uint64
+func (c *StructCompiler) checkUInt(value uint64, kind reflect.Kind) error {
+	var maxV uint64
 
 	switch kind {
 	case reflect.Uint8:
@@ -175,20 +244,16 @@ func (c *StructCompiler) checkUInt(astV ast.Int, kind reflect.Kind) error {
 		maxV = math.MaxUint16
 	case reflect.Uint32:
 		maxV = math.MaxUint32
-	case reflect.Uint64:
-		maxV = math.MaxInt64
+	case reflect.Uint64, reflect.Uintptr:
+		maxV = math.MaxUint64
 	case reflect.Uint:
-		maxV = math.MaxInt
+		maxV = math.MaxUint
 	default:
 		return errors.Wrapf(ErrInvalidType, "expected uint, got [%s]", kind)
 	}
 
-	if astV.Value() > int64(maxV) {
-		return errors.Wrapf(ErrTypeOverflow, "unsigned integer value overflow: actual value: [%d], max value: [%d], kind: [%s]", astV.Value(), maxV, kind)
-	}
-
-	if astV.Value() < int64(minV) {
-		return errors.Wrapf(ErrTypeOverflow, "unsigned integer value overflow: actual value: [%d], min value: [%d], kind: [%s]", astV.Value(), minV, kind)
+	if value > maxV {
+		return errors.Wrapf(ErrTypeOverflow, "unsigned integer value overflow: actual value: [%d], max value: [%d], kind: [%s]", value, maxV, kind)
 	}
 
 	return nil
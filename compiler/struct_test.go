@@ -3,6 +3,7 @@ package compiler_test
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/atmxlab/atmc/compiler"
 	linkedast "github.com/atmxlab/atmc/linker/ast"
@@ -94,6 +95,144 @@ func TestStruct(t *testing.T) {
 		require.Equal(t, expected, v)
 	})
 
+	t.Run("with_quoted_keys", func(t *testing.T) {
+		t.Parallel()
+
+		type Headers struct {
+			RequestID string `atmc:"x-request-id"`
+			Port      int    `atmc:"8080"`
+		}
+
+		a := testlinkedast.
+			NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.KV2("x-request-id", linkedast.NewString("abc"))
+				ob.KV2("8080", linkedast.NewInt(8080))
+			}).
+			Build()
+
+		c := compiler.NewStructCompiler("atmc")
+
+		var v Headers
+		err := c.Compile(&v, a)
+		require.NoError(t, err)
+
+		require.Equal(t, Headers{RequestID: "abc", Port: 8080}, v)
+	})
+
+	t.Run("with_null", func(t *testing.T) {
+		t.Parallel()
+
+		a := testlinkedast.
+			NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.KV2("field_str", linkedast.NewNull())
+				ob.KV2("field_int_ptr", linkedast.NewNull())
+				ob.KV2("field_array", linkedast.NewNull())
+				ob.KV2("field_struct", linkedast.NewNull())
+				ob.KV2("field_nested_test_type", linkedast.NewNull())
+				ob.KV2(
+					"field_test_type_ptr_array",
+					testlinkedast.NewArrayBuilder().
+						Element(linkedast.NewNull()).
+						Build(),
+				)
+			}).
+			Build()
+
+		c := compiler.NewStructCompiler("atmc")
+
+		v := TestType{
+			FieldStr:            "value",
+			FieldIntPtr:         lo.ToPtr(1),
+			FieldArray:          []string{"value"},
+			FieldNestedTestType: &TestType{},
+		}
+		v.FieldStruct.FieldInt = 1
+
+		err := c.Compile(&v, a)
+		require.NoError(t, err)
+
+		require.Equal(t, TestType{FieldTestTypePtrArray: []*TestType{nil}}, v)
+	})
+
+	t.Run("with_duration_and_byte_size", func(t *testing.T) {
+		t.Parallel()
+
+		type Limits struct {
+			Timeout   time.Duration   `atmc:"timeout"`
+			Retries   []time.Duration `atmc:"retries"`
+			Buffer    int             `atmc:"buffer"`
+			MaxUpload uint64          `atmc:"max_upload"`
+			Small     *int32          `atmc:"small"`
+		}
+
+		a := testlinkedast.
+			NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.KV2("timeout", linkedast.NewDuration(90*time.Second))
+				ob.KV2(
+					"retries",
+					testlinkedast.NewArrayBuilder().
+						Element(linkedast.NewDuration(250*time.Millisecond)).
+						Element(linkedast.NewDuration(time.Second)).
+						Build(),
+				)
+				ob.KV2("buffer", linkedast.NewByteSize(512<<20))
+				ob.KV2("max_upload", linkedast.NewByteSize(10_000_000_000))
+				ob.KV2("small", linkedast.NewByteSize(1024))
+			}).
+			Build()
+
+		c := compiler.NewStructCompiler("atmc")
+
+		var v Limits
+		err := c.Compile(&v, a)
+		require.NoError(t, err)
+
+		require.Equal(t, Limits{
+			Timeout:   90 * time.Second,
+			Retries:   []time.Duration{250 * time.Millisecond, time.Second},
+			Buffer:    512 << 20,
+			MaxUpload: 10_000_000_000,
+			Small:     lo.ToPtr(int32(1024)),
+		}, v)
+	})
+
+	t.Run("with_duration_in_not_duration_field", func(t *testing.T) {
+		t.Parallel()
+
+		a := testlinkedast.
+			NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.KV2("field_int64", linkedast.NewDuration(time.Second))
+			}).
+			Build()
+
+		c := compiler.NewStructCompiler("atmc")
+
+		var v TestType
+		err := c.Compile(&v, a)
+		require.ErrorIs(t, err, compiler.ErrInvalidType)
+	})
+
+	t.Run("with_byte_size_overflow", func(t *testing.T) {
+		t.Parallel()
+
+		a := testlinkedast.
+			NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.KV2("field_uint16", linkedast.NewByteSize(1<<20))
+			}).
+			Build()
+
+		c := compiler.NewStructCompiler("atmc")
+
+		var v TestType
+		err := c.Compile(&v, a)
+		require.ErrorIs(t, err, compiler.ErrTypeOverflow)
+	})
+
 	t.Run("with_ptr", func(t *testing.T) {
 		t.Parallel()
 
@@ -425,4 +564,46 @@ func TestStruct(t *testing.T) {
 
 		require.Equal(t, expected, v)
 	})
+
+	t.Run("with_array_root", func(t *testing.T) {
+		t.Parallel()
+
+		a := testlinkedast.
+			NewBuilder().
+			Root(testlinkedast.NewArrayBuilder().
+				Element(testlinkedast.NewObjectBuilder().KV2("field_str", linkedast.NewString("first")).Build()).
+				Element(testlinkedast.NewObjectBuilder().KV2("field_int", linkedast.NewInt(2)).Build()).
+				Build(),
+			).
+			Build()
+
+		c := compiler.NewStructCompiler("atmc")
+
+		var v []TestType
+		err := c.Compile(&v, a)
+		require.NoError(t, err)
+		require.Equal(t, []TestType{{FieldStr: "first"}, {FieldInt: 2}}, v)
+
+		var s TestType
+		err = c.Compile(&s, a)
+		require.ErrorIs(t, err, compiler.ErrInvalidType)
+		require.ErrorContains(t, err, "expected slice for array document root, got [struct]")
+	})
+
+	t.Run("with_scalar_root", func(t *testing.T) {
+		t.Parallel()
+
+		a := testlinkedast.NewBuilder().Root(linkedast.NewInt(8080)).Build()
+
+		c := compiler.NewStructCompiler("atmc")
+
+		var port uint16
+		err := c.Compile(&port, a)
+		require.NoError(t, err)
+		require.Equal(t, uint16(8080), port)
+
+		err = c.Compile(port, a)
+		require.ErrorIs(t, err, compiler.ErrInvalidType)
+		require.ErrorContains(t, err, "expected pointer for int document root, got [uint16]")
+	})
 }
@@ -5,6 +5,14 @@ import (
 	"github.com/atmxlab/atmc/types"
 )
 
+var (
+	ErrInvalidEscape = errors.New("invalid escape sequence")
+)
+
 func unexpectedTokenError(pos types.Position) error {
 	return errors.Newf("unexpected token at %v:%v", pos.Line(), pos.Column())
 }
+
+func positionedError(err error, pos types.Position) error {
+	return errors.Wrapf(err, "at %v:%v", pos.Line(), pos.Column())
+}
@@ -0,0 +1,164 @@
+package lexer
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/atmxlab/atmc/pkg/errors"
+)
+
+// unescape декодирует escape-последовательности в содержимом строкового литерала.
+// Поддерживается набор последовательностей как в Go (плюс \/ из JSON и \$ для отмены интерполяции).
+// При ошибке возвращается смещение (в байтах) невалидной последовательности внутри s.
+func unescape(s string) (string, int, error) {
+	if !strings.ContainsRune(s, '\\') {
+		return s, 0, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+
+		if i+1 >= len(s) {
+			return "", i, errors.Wrap(ErrInvalidEscape, `unterminated escape sequence "\"`)
+		}
+
+		switch c := s[i+1]; c {
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case '\\', '\'', '"', '/', '$':
+			b.WriteByte(c)
+		case 'x':
+			v, ok := parseHex(s[i+2:], 2)
+			if !ok {
+				return "", i, errors.Wrapf(ErrInvalidEscape, `expected 2 hex digits after "\x"`)
+			}
+
+			b.WriteByte(byte(v))
+			i += 4
+			continue
+		case 'u', 'U':
+			size := 4
+			if c == 'U' {
+				size = 8
+			}
+
+			v, ok := parseHex(s[i+2:], size)
+			if !ok {
+				return "", i, errors.Wrapf(ErrInvalidEscape, `expected %d hex digits after "\%c"`, size, c)
+			}
+
+			r := rune(v)
+			consumed := 2 + size
+
+			// Суррогатные пары, как в JSON: \uD83D\uDE00.
+			if c == 'u' && utf16.IsSurrogate(r) {
+				rest := s[i+consumed:]
+				if len(rest) < 6 || rest[0] != '\\' || rest[1] != 'u' {
+					return "", i, errors.Wrapf(ErrInvalidEscape, "unpaired surrogate %s", s[i:i+consumed])
+				}
+
+				low, ok := parseHex(rest[2:], 4)
+				if !ok {
+					return "", i, errors.Wrapf(ErrInvalidEscape, "unpaired surrogate %s", s[i:i+consumed])
+				}
+
+				r = utf16.DecodeRune(r, rune(low))
+				if r == utf8.RuneError {
+					return "", i, errors.Wrapf(ErrInvalidEscape, "invalid surrogate pair %s", s[i:i+consumed+6])
+				}
+
+				consumed += 6
+			}
+
+			if !utf8.ValidRune(r) {
+				return "", i, errors.Wrapf(ErrInvalidEscape, "invalid unicode code point %s", s[i:i+consumed])
+			}
+
+			b.WriteRune(r)
+			i += consumed
+			continue
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			v, ok := parseOctal(s[i+1:])
+			if !ok {
+				return "", i, errors.Wrapf(ErrInvalidEscape, `expected 3 octal digits after "\"`)
+			}
+
+			b.WriteByte(byte(v))
+			i += 4
+			continue
+		default:
+			r, _ := utf8.DecodeRuneInString(s[i+1:])
+			return "", i, errors.Wrapf(ErrInvalidEscape, `unknown escape sequence "\%c"`, r)
+		}
+
+		i += 2
+	}
+
+	return b.String(), 0, nil
+}
+
+func parseHex(s string, size int) (uint32, bool) {
+	if len(s) < size {
+		return 0, false
+	}
+
+	var v uint32
+	for i := 0; i < size; i++ {
+		c := s[i]
+
+		switch {
+		case '0' <= c && c <= '9':
+			v = v<<4 | uint32(c-'0')
+		case 'a' <= c && c <= 'f':
+			v = v<<4 | uint32(c-'a'+10)
+		case 'A' <= c && c <= 'F':
+			v = v<<4 | uint32(c-'A'+10)
+		default:
+			return 0, false
+		}
+	}
+
+	return v, true
+}
+
+func parseOctal(s string) (uint32, bool) {
+	if len(s) < 3 {
+		return 0, false
+	}
+
+	var v uint32
+	for i := 0; i < 3; i++ {
+		c := s[i]
+		if c < '0' || c > '7' {
+			return 0, false
+		}
+
+		v = v<<3 | uint32(c-'0')
+	}
+
+	if v > 255 {
+		return 0, false
+	}
+
+	return v, true
+}
@@ -0,0 +1,62 @@
+package lexer
+
+import (
+	"strings"
+)
+
+// trimIndent убирает общий отступ у строк многострочной строки.
+// Первая строка отбрасывается, если пустая (перенос сразу после открывающих кавычек),
+// последняя - если состоит только из пробелов (отступ перед закрывающими кавычками).
+func trimIndent(s string) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) == 1 {
+		return s
+	}
+
+	if isBlank(lines[0]) {
+		lines = lines[1:]
+	}
+
+	if len(lines) > 0 && isBlank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent, found := "", false
+	for _, line := range lines {
+		if isBlank(line) {
+			continue
+		}
+
+		lineIndent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if !found {
+			indent, found = lineIndent, true
+			continue
+		}
+
+		indent = commonPrefix(indent, lineIndent)
+	}
+
+	for i, line := range lines {
+		if isBlank(line) {
+			lines[i] = ""
+			continue
+		}
+
+		lines[i] = strings.TrimPrefix(line, indent)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
+}
+
+func commonPrefix(a, b string) string {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return a[:i]
+}
@@ -1,6 +1,8 @@
 package lexer
 
 import (
+	"strings"
+
 	"github.com/atmxlab/atmc/types"
 	"github.com/atmxlab/atmc/types/token"
 )
@@ -9,6 +11,8 @@ type Lexer struct {
 	input    string
 	tokens   []token.Token
 	location types.Location
+	// Глубина вложенности скобок. Пути импортов распознаются только на верхнем уровне.
+	depth int
 }
 
 func (l *Lexer) Location() types.Location {
@@ -23,7 +27,17 @@ func New() *Lexer {
 }
 
 func (l *Lexer) Tokenize(input string) ([]token.Token, error) {
+	l.depth = 0
+
+	return l.tokenize(input, types.NewInitialLocation())
+}
+
+// tokenize разбирает input, начиная с позиции location.
+// Отдельная начальная позиция нужна для выражений внутри интерполяции строк.
+func (l *Lexer) tokenize(input string, location types.Location) ([]token.Token, error) {
 	l.input = input
+	l.tokens = make([]token.Token, 0)
+	l.location = location
 
 	orderedTokenTypes := token.OrderedTokenTypes()
 
@@ -31,6 +45,10 @@ func (l *Lexer) Tokenize(input string) ([]token.Token, error) {
 		matched := false
 
 		for _, t := range orderedTokenTypes {
+			if l.skip(t) {
+				continue
+			}
+
 			value, exists := l.find(t)
 			if !exists {
 				continue
@@ -39,7 +57,7 @@ func (l *Lexer) Tokenize(input string) ([]token.Token, error) {
 			matched = true
 
 			switch t {
-			case token.WS, token.Comma, token.Comment:
+			case token.WS, token.Comma, token.Comment, token.BlockComment:
 				// noop - ignore ws, comma and comments
 			case token.EOL:
 				l.location = l.location.SetEnd(
@@ -51,7 +69,11 @@ func (l *Lexer) Tokenize(input string) ([]token.Token, error) {
 					l.location.End(),
 				)
 			default:
-				l.addToken(t, value)
+				if err := l.addToken(t, value); err != nil {
+					return nil, err
+				}
+
+				l.trackDepth(t)
 			}
 
 			break
@@ -69,9 +91,114 @@ func (l *Lexer) Tokenize(input string) ([]token.Token, error) {
 	return result, nil
 }
 
-func (l *Lexer) addToken(t token.Type, value string) {
+// skip отбрасывает типы токенов, которые неуместны в текущем контексте.
+func (l *Lexer) skip(t token.Type) bool {
+	switch t {
+	case token.Path:
+		return !l.pathAllowed()
+	case token.Slash:
+		// Незакрытый блочный комментарий - ошибка, а не деление и умножение.
+		return strings.HasPrefix(l.input, "/*")
+	case token.Int, token.Float, token.Duration:
+		return l.signIsOperator()
+	default:
+		return false
+	}
+}
+
+// pathAllowed сообщает, может ли в начале input быть путь импорта: на верхнем уровне через пробел после имени импорта.
+// Если имя - операнд выражения (half = total /2, a + b /2, x.y /2), "/" - оператор деления.
+func (l *Lexer) pathAllowed() bool {
+	if l.depth > 0 || len(l.tokens) == 0 {
+		return false
+	}
+
+	name := l.tokens[len(l.tokens)-1]
+	if name.Type() != token.Ident || name.Location().End() == l.location.End() {
+		return false
+	}
+
+	if len(l.tokens) == 1 {
+		return true
+	}
+
+	switch l.tokens[len(l.tokens)-2].Type() {
+	case token.Assign, token.Dot, token.Dollar, token.Colon, token.Coalesce, token.Bang,
+		token.Plus, token.Minus, token.Star, token.Slash, token.Percent,
+		token.Eq, token.NotEq, token.Lt, token.LtEq, token.Gt, token.GtEq, token.And, token.Or,
+		token.If, token.Then, token.Else:
+		return false
+	default:
+		return true
+	}
+}
+
+// signIsOperator сообщает, что "+" или "-" в начале input - бинарный оператор, а не знак числа.
+// Так происходит, если знак записан слитно с предыдущим значением: 1+2, a-1, (x)-1.
+// Через пробел знак относится к числу, поэтому [444 -321] - массив из двух элементов.
+func (l *Lexer) signIsOperator() bool {
+	if len(l.input) == 0 || (l.input[0] != '-' && l.input[0] != '+') || len(l.tokens) == 0 {
+		return false
+	}
+
+	last := l.tokens[len(l.tokens)-1]
+	if last.Location().End() != l.location.End() {
+		return false
+	}
+
+	switch last.Type() {
+	case token.Ident, token.Int, token.Float, token.Duration, token.ByteSize,
+		token.String, token.RawString, token.MultilineString, token.TemplateEnd,
+		token.Bool, token.Null, token.RParen, token.RBracket, token.RBrace:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *Lexer) trackDepth(t token.Type) {
+	switch t {
+	case token.LBrace, token.LBracket, token.LParen:
+		l.depth++
+	case token.RBrace, token.RBracket, token.RParen:
+		if l.depth > 0 {
+			l.depth--
+		}
+	}
+}
+
+func (l *Lexer) addToken(t token.Type, value string) error {
 	value = t.Postprocess(value)
 
+	switch t {
+	case token.String:
+		if parts := splitTemplate(value); hasInterpolation(parts) {
+			return l.addTemplate(value, parts)
+		}
+
+		unescaped, offset, err := unescape(value)
+		if err != nil {
+			// +1 - открывающая кавычка.
+			return positionedError(err, positionAt(l.location.Start(), value, offset, 1))
+		}
+
+		value = unescaped
+	case token.MultilineString:
+		// Escape-последовательности проверяются до удаления отступов, чтобы позиция ошибки была точной.
+		// Удаление отступов не затрагивает escape-последовательности.
+		if _, offset, err := unescape(value); err != nil {
+			// +3 - открывающие кавычки.
+			return positionedError(err, positionAt(l.location.Start(), value, offset, 3))
+		}
+
+		unescaped, _, err := unescape(trimIndent(value))
+		if err != nil {
+			return positionedError(err, l.location.Start())
+		}
+
+		value = unescaped
+	}
+
 	tok := token.New(
 		t,
 		token.Value(value),
@@ -79,6 +206,81 @@ func (l *Lexer) addToken(t token.Type, value string) {
 	)
 
 	l.tokens = append(l.tokens, tok)
+
+	return nil
+}
+
+// addTemplate разбивает строку с интерполяцией на токены:
+// TemplateStart, литералы (String), Interpolation + токены выражения + RBrace, TemplateEnd.
+func (l *Lexer) addTemplate(value string, parts []templatePart) error {
+	start := l.location.Start()
+	// +1 - открывающая кавычка.
+	at := func(offset int) types.Position {
+		return positionAt(start, value, offset, 1)
+	}
+
+	l.tokens = append(l.tokens, token.New(token.TemplateStart, `"`, types.NewLocation(start, at(0))))
+
+	for _, part := range parts {
+		if !part.interpolation {
+			unescaped, offset, err := unescape(value[part.start:part.end])
+			if err != nil {
+				return positionedError(err, at(part.start+offset))
+			}
+
+			l.tokens = append(l.tokens, token.New(
+				token.String,
+				token.Value(unescaped),
+				types.NewLocation(at(part.start), at(part.end)),
+			))
+
+			continue
+		}
+
+		l.tokens = append(l.tokens, token.New(
+			token.Interpolation,
+			"${",
+			types.NewLocation(at(part.start-2), at(part.start)),
+		))
+
+		// Выражение интерполяции всегда вложено в значение.
+		tokens, err := (&Lexer{depth: 1}).tokenize(
+			value[part.start:part.end],
+			types.NewLocation(at(part.start), at(part.start)),
+		)
+		if err != nil {
+			return err
+		}
+
+		l.tokens = append(l.tokens, tokens...)
+		l.tokens = append(l.tokens, token.New(
+			token.RBrace,
+			"}",
+			types.NewLocation(at(part.end), at(part.end+1)),
+		))
+	}
+
+	l.tokens = append(l.tokens, token.New(token.TemplateEnd, `"`, types.NewLocation(at(len(value)), l.location.End())))
+
+	return nil
+}
+
+// positionAt вычисляет позицию символа по смещению offset внутри value,
+// который начинается через shift байт после start.
+func positionAt(start types.Position, value string, offset int, shift uint) types.Position {
+	pos := start.AddPos(shift).AddColumn(shift)
+
+	for i := 0; i < offset && i < len(value); i++ {
+		pos = pos.IncrPos()
+
+		if value[i] == '\n' {
+			pos = pos.IncrLine().ResetColumn()
+		} else {
+			pos = pos.IncrColumn()
+		}
+	}
+
+	return pos
 }
 
 func (l *Lexer) find(t token.Type) (value string, exists bool) {
@@ -95,6 +297,22 @@ func (l *Lexer) find(t token.Type) (value string, exists bool) {
 	l.location = l.location.SetStart(
 		l.location.End(),
 	)
+
+	// Токен может занимать несколько строк (многострочные строки и комментарии).
+	if lines := strings.Count(value, "\n"); lines > 0 && t != token.EOL {
+		lastLine := value[strings.LastIndex(value, "\n")+1:]
+
+		l.location = l.location.SetEnd(
+			l.location.End().
+				AddPos(uint(end)).
+				AddLine(uint(lines)).
+				ResetColumn().
+				AddColumn(uint(len(lastLine))),
+		)
+
+		return value, true
+	}
+
 	l.location = l.location.SetEnd(
 		l.location.End().
 			AddPos(uint(end)).
@@ -158,6 +158,75 @@ func TestLexer_Tokenize_TokenTypes(t *testing.T) {
 				token2.Spread,
 			},
 		},
+		{
+			name: "block comment",
+			input: `common... /* multi
+line comment */ key: 1`,
+			expectedTypes: []token2.Type{
+				token2.Ident,
+				token2.Spread,
+				token2.Ident,
+				token2.Colon,
+				token2.Int,
+			},
+		},
+		{
+			name: "doc comment",
+			input: `/// doc comment
+//// separator is a simple comment
+key: 1`,
+			expectedTypes: []token2.Type{
+				token2.DocComment,
+				token2.Ident,
+				token2.Colon,
+				token2.Int,
+			},
+		},
+		{
+			name:          "unterminated block comment",
+			input:         `/* comment`,
+			expectedTypes: []token2.Type{},
+			hasError:      true,
+		},
+		{
+			name:  "signs and operators",
+			input: `{a: 1+2 b: [1 -2] c: x-1 d: (x)-1 e: x - -1}`,
+			expectedTypes: []token2.Type{
+				token2.LBrace,
+				token2.Ident, token2.Colon, token2.Int, token2.Plus, token2.Int,
+				token2.Ident, token2.Colon, token2.LBracket, token2.Int, token2.Int, token2.RBracket,
+				token2.Ident, token2.Colon, token2.Ident, token2.Minus, token2.Int,
+				token2.Ident, token2.Colon, token2.LParen, token2.Ident, token2.RParen, token2.Minus, token2.Int,
+				token2.Ident, token2.Colon, token2.Ident, token2.Minus, token2.Int,
+				token2.RBrace,
+			},
+		},
+		{
+			name:  "division inside values and paths at top level",
+			input: `common ./common.atmx {a: 10/2 b: x % 3 c: !x && y || a <= b != c >= d}`,
+			expectedTypes: []token2.Type{
+				token2.Ident,
+				token2.Path,
+				token2.LBrace,
+				token2.Ident, token2.Colon, token2.Int, token2.Slash, token2.Int,
+				token2.Ident, token2.Colon, token2.Ident, token2.Percent, token2.Int,
+				token2.Ident, token2.Colon,
+				token2.Bang, token2.Ident, token2.And, token2.Ident, token2.Or,
+				token2.Ident, token2.LtEq, token2.Ident, token2.NotEq, token2.Ident, token2.GtEq, token2.Ident,
+				token2.RBrace,
+			},
+		},
+		{
+			name:  "division inside top level definitions",
+			input: "total = 10\nhalf = total/2\nthird = total /3\nquarter = common.total /4\ncommon ./common.atmx",
+			expectedTypes: []token2.Type{
+				token2.Ident, token2.Assign, token2.Int,
+				token2.Ident, token2.Assign, token2.Ident, token2.Slash, token2.Int,
+				token2.Ident, token2.Assign, token2.Ident, token2.Slash, token2.Int,
+				token2.Ident, token2.Assign, token2.Ident, token2.Dot, token2.Ident, token2.Slash, token2.Int,
+				token2.Ident, token2.Path,
+			},
+		},
 		{
 			name:  "nested import import",
 			input: `common /dir1/dir2/common.atmx`,
@@ -364,6 +433,49 @@ common ./common.atmc
 				token2.RBrace,
 			},
 		},
+		{
+			name:  "glob and directory imports",
+			input: "services ./services/*.atmc\nall ./services/",
+			expectedTypes: []token2.Type{
+				token2.Ident,
+				token2.Path,
+				token2.Ident,
+				token2.Path,
+			},
+		},
+		{
+			name:  "optional import",
+			input: "local ?./local.atmc",
+			expectedTypes: []token2.Type{
+				token2.Ident,
+				token2.Path,
+			},
+		},
+		{
+			name:  "merge strategies",
+			input: `{level +: ["debug"] brokers^: [1] logging =: {x: 1}}`,
+			expectedTypes: []token2.Type{
+				token2.LBrace,
+				token2.Ident,
+				token2.AppendColon,
+				token2.LBracket,
+				token2.String,
+				token2.RBracket,
+				token2.Ident,
+				token2.PrependColon,
+				token2.LBracket,
+				token2.Int,
+				token2.RBracket,
+				token2.Ident,
+				token2.ReplaceColon,
+				token2.LBrace,
+				token2.Ident,
+				token2.Colon,
+				token2.Int,
+				token2.RBrace,
+				token2.RBrace,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -386,3 +498,249 @@ common ./common.atmc
 		})
 	}
 }
+
+func TestLexer_Tokenize_StringEscapes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		input         string
+		expectedValue string
+	}{
+		{
+			name:          "without escapes",
+			input:         `"plain text"`,
+			expectedValue: "plain text",
+		},
+		{
+			name:          "simple escapes",
+			input:         `"line\nbreak\ttab\\slash\/"`,
+			expectedValue: "line\nbreak\ttab\\slash/",
+		},
+		{
+			name:          "escaped quotes",
+			input:         `"\"quoted\""`,
+			expectedValue: `"quoted"`,
+		},
+		{
+			name:          "only escaped quote",
+			input:         `"\""`,
+			expectedValue: `"`,
+		},
+		{
+			name:          "unicode",
+			input:         `"\u00e9 \U0001F600 \u00E9"`,
+			expectedValue: "é 😀 é",
+		},
+		{
+			name:          "surrogate pair",
+			input:         `"\uD83D\uDE00"`,
+			expectedValue: "😀",
+		},
+		{
+			name:          "hex and octal",
+			input:         `"\x41\102"`,
+			expectedValue: "AB",
+		},
+		{
+			name:          "escaped interpolation",
+			input:         `"\${db.user}"`,
+			expectedValue: "${db.user}",
+		},
+		{
+			name:          "dollar without interpolation",
+			input:         `"cost: $5 ${"`,
+			expectedValue: "cost: $5 ${",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := lexer.New()
+
+			tokens, err := l.Tokenize(tc.input)
+			require.NoError(t, err)
+			require.Len(t, tokens, 1)
+			require.Equal(t, token2.String, tokens[0].Type())
+			require.Equal(t, tc.expectedValue, tokens[0].Value().String())
+		})
+	}
+}
+
+func TestLexer_Tokenize_InvalidStringEscapes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		input         string
+		expectedError string
+	}{
+		{
+			name:          "unknown escape",
+			input:         `{key: "te\qst"}`,
+			expectedError: `at 1:9`,
+		},
+		{
+			name: "short unicode on second line",
+			input: `{
+	key: "abc\u12"
+}`,
+			expectedError: `at 2:10`,
+		},
+		{
+			name:          "unpaired surrogate",
+			input:         `"\uD83D"`,
+			expectedError: `unpaired surrogate`,
+		},
+		{
+			name:          "short hex",
+			input:         `"\x4"`,
+			expectedError: `expected 2 hex digits`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := lexer.New()
+
+			_, err := l.Tokenize(tc.input)
+			require.ErrorIs(t, err, lexer.ErrInvalidEscape)
+			require.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestLexer_Tokenize_StringInterpolation(t *testing.T) {
+	t.Parallel()
+
+	loc := func(startColumn, endColumn uint) types.Location {
+		return types.NewLocation(
+			types.NewPosition(1, startColumn, startColumn),
+			types.NewPosition(1, endColumn, endColumn),
+		)
+	}
+
+	l := lexer.New()
+
+	tokens, err := l.Tokenize(`"a${db.user}:${$PORT}"`)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]token2.Token{
+			token2.New(token2.TemplateStart, `"`, loc(0, 1)),
+			token2.New(token2.String, "a", loc(1, 2)),
+			token2.New(token2.Interpolation, "${", loc(2, 4)),
+			token2.New(token2.Ident, "db", loc(4, 6)),
+			token2.New(token2.Dot, ".", loc(6, 7)),
+			token2.New(token2.Ident, "user", loc(7, 11)),
+			token2.New(token2.RBrace, "}", loc(11, 12)),
+			token2.New(token2.String, ":", loc(12, 13)),
+			token2.New(token2.Interpolation, "${", loc(13, 15)),
+			token2.New(token2.Dollar, "$", loc(15, 16)),
+			token2.New(token2.Ident, "PORT", loc(16, 20)),
+			token2.New(token2.RBrace, "}", loc(20, 21)),
+			token2.New(token2.TemplateEnd, `"`, loc(21, 22)),
+		},
+		tokens,
+	)
+
+	_, err = l.Tokenize(`{key: "a${db.#}"}`)
+	require.ErrorContains(t, err, "unexpected token at 1:13")
+
+	_, err = l.Tokenize(`{key: "\q${db}"}`)
+	require.ErrorIs(t, err, lexer.ErrInvalidEscape)
+	require.ErrorContains(t, err, "at 1:7")
+}
+
+func TestLexer_Tokenize_MultilineStrings(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		input         string
+		expectedType  token2.Type
+		expectedValue string
+	}{
+		{
+			name:          "raw string",
+			input:         "`C:\\dir\\n\n  second line`",
+			expectedType:  token2.RawString,
+			expectedValue: "C:\\dir\\n\n  second line",
+		},
+		{
+			name: "multiline string with indentation",
+			input: `"""
+		SELECT *
+		  FROM users
+		 WHERE id = 1
+		"""`,
+			expectedType:  token2.MultilineString,
+			expectedValue: "SELECT *\n  FROM users\n WHERE id = 1",
+		},
+		{
+			name: "multiline string with escapes and blank lines",
+			input: `"""
+    line1\t"tab"
+
+    line2
+"""`,
+			expectedType:  token2.MultilineString,
+			expectedValue: "line1\t\"tab\"\n\nline2",
+		},
+		{
+			name:          "one line multiline string",
+			input:         `"""one "quoted" line"""`,
+			expectedType:  token2.MultilineString,
+			expectedValue: `one "quoted" line`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := lexer.New()
+
+			tokens, err := l.Tokenize(tc.input)
+			require.NoError(t, err)
+			require.Len(t, tokens, 1)
+			require.Equal(t, tc.expectedType, tokens[0].Type())
+			require.Equal(t, tc.expectedValue, tokens[0].Value().String())
+		})
+	}
+}
+
+func TestLexer_Tokenize_MultilineStringLocation(t *testing.T) {
+	t.Parallel()
+
+	input := "{cert: `line1\nline2\nline3` key: \"\"\"\n  a \\q\n\"\"\"}"
+
+	l := lexer.New()
+
+	_, err := l.Tokenize(input)
+	require.ErrorIs(t, err, lexer.ErrInvalidEscape)
+	require.ErrorContains(t, err, "at 4:4")
+
+	tokens, err := l.Tokenize("{cert: `line1\nline2\nline3` key: 1}")
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		types.NewLocation(
+			types.NewPosition(1, 7, 7),
+			types.NewPosition(3, 6, 26),
+		),
+		tokens[3].Location(),
+	)
+	require.Equal(
+		t,
+		types.NewLocation(
+			types.NewPosition(3, 7, 27),
+			types.NewPosition(3, 10, 30),
+		),
+		tokens[4].Location(),
+	)
+}
@@ -0,0 +1,59 @@
+package lexer
+
+import "strings"
+
+// templatePart - часть содержимого строки: литерал или выражение интерполяции.
+// start и end - смещения в байтах; у выражения они не включают "${" и "}".
+type templatePart struct {
+	start         int
+	end           int
+	interpolation bool
+}
+
+// splitTemplate разбивает содержимое строки на литералы и выражения ${...}.
+// Экранированный "\${" интерполяцией не считается.
+func splitTemplate(s string) []templatePart {
+	parts := make([]templatePart, 0)
+	start := 0
+
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '\\':
+			i += 2
+		case strings.HasPrefix(s[i:], "${"):
+			size := strings.IndexByte(s[i+2:], '}')
+			if size < 0 {
+				// Незакрытая "${" остается частью литерала.
+				i++
+				continue
+			}
+
+			if start < i {
+				parts = append(parts, templatePart{start: start, end: i})
+			}
+
+			parts = append(parts, templatePart{start: i + 2, end: i + 2 + size, interpolation: true})
+
+			i += 2 + size + 1
+			start = i
+		default:
+			i++
+		}
+	}
+
+	if start < len(s) {
+		parts = append(parts, templatePart{start: start, end: len(s)})
+	}
+
+	return parts
+}
+
+func hasInterpolation(parts []templatePart) bool {
+	for _, part := range parts {
+		if part.interpolation {
+			return true
+		}
+	}
+
+	return false
+}
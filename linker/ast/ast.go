@@ -5,23 +5,30 @@ import (
 )
 
 type Ast struct {
-	object Object
+	root Expression
 }
 
-func (a Ast) Object() Object {
-	return a.object
+// Root корневое значение документа: объект, массив или скаляр.
+func (a Ast) Root() Expression {
+	return a.root
 }
 
-func NewAst(object Object) Ast {
-	return Ast{object: object}
+// Object корневой объект документа. Второе значение false, если корень не объект.
+func (a Ast) Object() (Object, bool) {
+	obj, ok := a.root.(Object)
+	return obj, ok
+}
+
+func NewAst(root Expression) Ast {
+	return Ast{root: root}
 }
 
 func (a Ast) FindExpByPath(path []Ident) (Expression, error) {
 	if len(path) == 0 {
-		return a.object, nil
+		return a.root, nil
 	}
 
-	foundNode, err := a.object.FindExpByPath(path)
+	foundNode, err := FindExpByPath(a.root, path)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error finding node by path")
 	}
@@ -0,0 +1,12 @@
+package ast
+
+// Delete маркер удаления ключа. Живет только во время линковки:
+// при слиянии заменяет значение ключа, а из итогового AST ключ удаляется вместе с маркером.
+type Delete struct {
+	node
+	expression
+}
+
+func NewDelete() Delete {
+	return Delete{}
+}
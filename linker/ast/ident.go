@@ -1,14 +1,28 @@
 package ast
 
+import "strconv"
+
 type Ident struct {
 	node
 	string
+	// Сегмент пути может быть индексом элемента массива: hosts[0].
+	index   int
+	isIndex bool
 }
 
 func NewIdent(string string) Ident {
 	return Ident{string: string}
 }
 
+// NewIndex сегмент пути - индекс элемента массива. Отрицательный индекс считается с конца.
+func NewIndex(index int) Ident {
+	return Ident{string: "[" + strconv.Itoa(index) + "]", index: index, isIndex: true}
+}
+
 func (i Ident) String() string {
 	return i.string
 }
+
+func (i Ident) Index() (int, bool) {
+	return i.index, i.isIndex
+}
@@ -0,0 +1,205 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/atmxlab/atmc/pkg/errors"
+)
+
+// DecodeJSON строит выражение из JSON. Порядок ключей объектов сохраняется,
+// целые числа становятся Int, остальные числа - Float.
+func DecodeJSON(data []byte) (Expression, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	exp, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after top-level JSON value")
+	}
+
+	return exp, nil
+}
+
+func decodeJSONValue(dec *json.Decoder) (Expression, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, errors.Wrap(err, "read JSON token")
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		switch v {
+		case '{':
+			return decodeJSONObject(dec)
+		case '[':
+			return decodeJSONArray(dec)
+		default:
+			return nil, errors.Newf("unexpected JSON delimiter %s", v)
+		}
+	case string:
+		return NewString(v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return NewInt(i), nil
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse JSON number %s", v)
+		}
+
+		return NewFloat(f), nil
+	case bool:
+		return NewBool(v), nil
+	case nil:
+		return NewNull(), nil
+	default:
+		return nil, errors.Newf("unexpected JSON token %v", v)
+	}
+}
+
+func decodeJSONObject(dec *json.Decoder) (Object, error) {
+	kvs := make([]KV, 0)
+	// Повторный ключ заменяет значение, как в encoding/json.
+	indexByKey := make(map[string]int)
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return Object{}, errors.Wrap(err, "read JSON key")
+		}
+
+		key, ok := tok.(string)
+		if !ok {
+			return Object{}, errors.Newf("unexpected JSON key %v", tok)
+		}
+
+		value, err := decodeJSONValue(dec)
+		if err != nil {
+			return Object{}, errors.Wrapf(err, "decode value of key %s", key)
+		}
+
+		if idx, exists := indexByKey[key]; exists {
+			kvs[idx] = NewKV(NewIdent(key), value)
+			continue
+		}
+
+		indexByKey[key] = len(kvs)
+		kvs = append(kvs, NewKV(NewIdent(key), value))
+	}
+
+	// Закрывающая скобка.
+	if _, err := dec.Token(); err != nil {
+		return Object{}, errors.Wrap(err, "read JSON token")
+	}
+
+	return NewObject(kvs), nil
+}
+
+func decodeJSONArray(dec *json.Decoder) (Array, error) {
+	elements := make([]Expression, 0)
+
+	for dec.More() {
+		value, err := decodeJSONValue(dec)
+		if err != nil {
+			return Array{}, errors.Wrapf(err, "decode element %d", len(elements))
+		}
+
+		elements = append(elements, value)
+	}
+
+	// Закрывающая скобка.
+	if _, err := dec.Token(); err != nil {
+		return Array{}, errors.Wrap(err, "read JSON token")
+	}
+
+	return NewArray(elements), nil
+}
+
+// EncodeJSON кодирует выражение в JSON. Порядок ключей объектов сохраняется,
+// duration кодируется строкой ("1m30s"), размер в байтах - числом байт.
+func EncodeJSON(exp Expression) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := encodeJSONValue(&buf, exp); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encodeJSONValue(buf *bytes.Buffer, exp Expression) error {
+	switch v := exp.(type) {
+	case Object:
+		buf.WriteByte('{')
+		for i, kv := range v.KV() {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := encodeJSONString(buf, kv.Key().String()); err != nil {
+				return err
+			}
+
+			buf.WriteByte(':')
+
+			if err := encodeJSONValue(buf, kv.Value()); err != nil {
+				return errors.Wrapf(err, "encode value of key %s", kv.Key().String())
+			}
+		}
+		buf.WriteByte('}')
+	case Array:
+		buf.WriteByte('[')
+		for i, elem := range v.Elements() {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := encodeJSONValue(buf, elem); err != nil {
+				return errors.Wrapf(err, "encode element %d", i)
+			}
+		}
+		buf.WriteByte(']')
+	case String:
+		return encodeJSONString(buf, v.Value())
+	case Int:
+		buf.WriteString(strconv.FormatInt(v.Value(), 10))
+	case Float:
+		data, err := json.Marshal(v.Value())
+		if err != nil {
+			return errors.Wrap(err, "encode float")
+		}
+
+		buf.Write(data)
+	case Bool:
+		buf.WriteString(strconv.FormatBool(v.Value()))
+	case Duration:
+		return encodeJSONString(buf, v.Value().String())
+	case ByteSize:
+		buf.WriteString(strconv.FormatUint(v.Value(), 10))
+	case Null:
+		buf.WriteString("null")
+	default:
+		return errors.Newf("unexpected expression type %T", exp)
+	}
+
+	return nil
+}
+
+func encodeJSONString(buf *bytes.Buffer, s string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return errors.Wrap(err, "encode string")
+	}
+
+	buf.Write(data)
+
+	return nil
+}
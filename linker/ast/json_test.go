@@ -0,0 +1,118 @@
+package ast_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atmxlab/atmc/linker/ast"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected ast.Expression
+		err      bool
+	}{
+		{
+			name:  "object keeps key order",
+			input: `{"b": 1, "a": [1.5, true, null, "x"], "b": {"c": -2}}`,
+			expected: ast.NewObject([]ast.KV{
+				ast.NewKV(ast.NewIdent("b"), ast.NewObject([]ast.KV{
+					ast.NewKV(ast.NewIdent("c"), ast.NewInt(-2)),
+				})),
+				ast.NewKV(ast.NewIdent("a"), ast.NewArray([]ast.Expression{
+					ast.NewFloat(1.5),
+					ast.NewBool(true),
+					ast.NewNull(),
+					ast.NewString("x"),
+				})),
+			}),
+		},
+		{
+			name:     "scalar",
+			input:    ` "host" `,
+			expected: ast.NewString("host"),
+		},
+		{
+			name:     "big number",
+			input:    `1e100`,
+			expected: ast.NewFloat(1e100),
+		},
+		{
+			name:  "invalid",
+			input: `{"a": }`,
+			err:   true,
+		},
+		{
+			name:  "trailing data",
+			input: `[1] [2]`,
+			err:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			exp, err := ast.DecodeJSON([]byte(tc.input))
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, exp)
+		})
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    ast.Expression
+		expected string
+	}{
+		{
+			name: "object keeps key order",
+			input: ast.NewObject([]ast.KV{
+				ast.NewKV(ast.NewIdent("b"), ast.NewInt(1)),
+				ast.NewKV(ast.NewIdent("a"), ast.NewArray([]ast.Expression{
+					ast.NewFloat(1.5),
+					ast.NewBool(true),
+					ast.NewNull(),
+					ast.NewString(`"x"`),
+				})),
+			}),
+			expected: `{"b":1,"a":[1.5,true,null,"\"x\""]}`,
+		},
+		{
+			name: "units",
+			input: ast.NewArray([]ast.Expression{
+				ast.NewDuration(90 * time.Second),
+				ast.NewByteSize(1024),
+			}),
+			expected: `["1m30s",1024]`,
+		},
+		{
+			name:     "empty object",
+			input:    ast.NewObject(nil),
+			expected: `{}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := ast.EncodeJSON(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, string(data))
+		})
+	}
+}
@@ -4,6 +4,10 @@ type KV struct {
 	Node
 	key   Ident
 	value Expression
+	doc   string
+	merge MergeStrategy
+	// Поле, по которому сливаются массивы объектов при MergeByKey.
+	mergeKey string
 }
 
 func NewKV(key Ident, value Expression) KV {
@@ -17,3 +21,34 @@ func (K KV) Key() Ident {
 func (K KV) Value() Expression {
 	return K.value
 }
+
+// Doc текст документирующего комментария ключа.
+func (K KV) Doc() string {
+	return K.doc
+}
+
+func (K KV) SetDoc(doc string) KV {
+	K.doc = doc
+	return K
+}
+
+// Merge способ слияния значения с уже существующим значением ключа.
+func (K KV) Merge() MergeStrategy {
+	return K.merge
+}
+
+func (K KV) SetMerge(merge MergeStrategy) KV {
+	K.merge = merge
+	return K
+}
+
+// MergeKey поле, по которому сливаются массивы объектов.
+func (K KV) MergeKey() string {
+	return K.mergeKey
+}
+
+func (K KV) SetMergeKey(key string) KV {
+	K.merge = MergeByKey
+	K.mergeKey = key
+	return K
+}
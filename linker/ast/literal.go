@@ -1,5 +1,7 @@
 package ast
 
+import "time"
+
 type literal[T comparable] struct {
 	node
 	expression
@@ -22,6 +24,18 @@ func NewFloat(i float64) Float {
 	return Float{value: i}
 }
 
+type Duration = literal[time.Duration]
+
+func NewDuration(i time.Duration) Duration {
+	return Duration{value: i}
+}
+
+type ByteSize = literal[uint64]
+
+func NewByteSize(i uint64) ByteSize {
+	return ByteSize{value: i}
+}
+
 type String = literal[string]
 
 func NewString(i string) String {
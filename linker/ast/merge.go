@@ -0,0 +1,33 @@
+package ast
+
+// MergeStrategy способ слияния значения ключа со значением, которое у ключа уже есть.
+// Нужен только на время линковки, в итоговом AST у всех ключей MergeDefault.
+type MergeStrategy uint8
+
+const (
+	// MergeDefault объекты сливаются рекурсивно, остальные значения заменяются.
+	MergeDefault MergeStrategy = iota
+	// MergeAppend массив дописывается в конец существующего.
+	MergeAppend
+	// MergePrepend массив дописывается в начало существующего.
+	MergePrepend
+	// MergeReplace значение заменяется целиком, в том числе объект.
+	MergeReplace
+	// MergeByKey массивы объектов сливаются по значению поля.
+	MergeByKey
+)
+
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeAppend:
+		return "+:"
+	case MergePrepend:
+		return "^:"
+	case MergeReplace:
+		return "=:"
+	case MergeByKey:
+		return "@merge"
+	default:
+		return ":"
+	}
+}
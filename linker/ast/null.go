@@ -0,0 +1,12 @@
+package ast
+
+// Null явное отсутствие значения.
+// В MapCompiler превращается в nil, в StructCompiler - в zero value поля.
+type Null struct {
+	node
+	expression
+}
+
+func NewNull() Null {
+	return Null{}
+}
@@ -4,9 +4,11 @@ import (
 	"strings"
 
 	"github.com/atmxlab/atmc/pkg/errors"
-	"github.com/samber/lo"
 )
 
+// ErrIndexOutOfRange индекс в пути переменной выходит за границы массива.
+var ErrIndexOutOfRange = errors.New("index out of range")
+
 type Object struct {
 	node
 	expression
@@ -43,27 +45,82 @@ func (o Object) FindExpByPath(path []Ident) (Expression, error) {
 }
 
 func (o Object) findExpByPath(path []Ident) (Expression, error) {
-	for _, kv := range o.kv {
-		if kv.Key().String() != path[0].String() {
-			continue
+	return findExpByPath(o, path, path)
+}
+
+// FindExpByPath находит значение по пути внутри объекта или массива.
+func FindExpByPath(exp Expression, path []Ident) (Expression, error) {
+	foundNode, err := findExpByPath(exp, path, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding expression by path")
+	}
+
+	return foundNode, nil
+}
+
+// findExpByPath спускается по пути через объекты и массивы. fullPath нужен для сообщений об ошибках.
+func findExpByPath(exp Expression, path, fullPath []Ident) (Expression, error) {
+	if len(path) == 0 {
+		return exp, nil
+	}
+
+	traversed := fullPath[:len(fullPath)-len(path)+1]
+
+	if index, ok := path[0].Index(); ok {
+		arr, ok := exp.(Array)
+		if !ok {
+			return nil, errors.NotFoundf(
+				"expression by path not found: %s is not an array",
+				FormatPath(traversed[:len(traversed)-1]),
+			)
 		}
 
-		if len(path) == 1 {
-			return kv.Value(), nil
+		i := index
+		if i < 0 {
+			i += len(arr.Elements())
 		}
 
-		switch v := kv.Value().(type) {
-		case Object:
-			return v.findExpByPath(path[1:])
-		default:
-			return nil, errors.New("unexpected value")
+		if i < 0 || i >= len(arr.Elements()) {
+			return nil, errors.Wrapf(
+				ErrIndexOutOfRange,
+				"%s: index %d out of range for array of length %d",
+				FormatPath(traversed),
+				index,
+				len(arr.Elements()),
+			)
 		}
+
+		return findExpByPath(arr.Elements()[i], path[1:], fullPath)
+	}
+
+	obj, ok := exp.(Object)
+	if !ok {
+		return nil, errors.NotFoundf(
+			"expression by path not found: %s is not an object",
+			FormatPath(traversed[:len(traversed)-1]),
+		)
+	}
+
+	for _, kv := range obj.kv {
+		if kv.Key().String() == path[0].String() {
+			return findExpByPath(kv.Value(), path[1:], fullPath)
+		}
+	}
+
+	return nil, errors.NotFoundf("expression by path not found: path: %s", FormatPath(path))
+}
+
+// FormatPath путь в том виде, как он записывается в конфиге: clusters[1].endpoint.
+func FormatPath(path []Ident) string {
+	var b strings.Builder
+
+	for i, item := range path {
+		if _, ok := item.Index(); !ok && i > 0 {
+			b.WriteString(".")
+		}
+
+		b.WriteString(item.String())
 	}
 
-	return nil, errors.NotFoundf("expression by path not found: path: %s", strings.Join(lo.Map(path, func(
-		item Ident,
-		index int,
-	) string {
-		return item.String()
-	}), "."))
+	return b.String()
 }
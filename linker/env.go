@@ -0,0 +1,61 @@
+package linker
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	ast3 "github.com/atmxlab/atmc/linker/ast"
+	ast2 "github.com/atmxlab/atmc/parser/ast"
+	"github.com/atmxlab/atmc/pkg/errors"
+)
+
+// convertEnv приводит значение переменной среды к указанному типу.
+func convertEnv(value string, typ ast2.EnvType) (ast3.Expression, error) {
+	switch typ {
+	case ast2.EnvTypeString:
+		return ast3.NewString(value), nil
+	case ast2.EnvTypeInt:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse int")
+		}
+
+		return ast3.NewInt(i), nil
+	case ast2.EnvTypeFloat:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse float")
+		}
+
+		// Для бесконечности и NaN нет значений ни в конфиге, ни в JSON.
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, errors.Newf("unsupported float %s", value)
+		}
+
+		return ast3.NewFloat(f), nil
+	case ast2.EnvTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse bool")
+		}
+
+		return ast3.NewBool(b), nil
+	case ast2.EnvTypeDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse duration")
+		}
+
+		return ast3.NewDuration(d), nil
+	case ast2.EnvTypeJSON:
+		exp, err := ast3.DecodeJSON([]byte(value))
+		if err != nil {
+			return nil, errors.Wrap(err, "decode json")
+		}
+
+		return exp, nil
+	default:
+		return nil, errors.Newf("unknown env type %s", typ)
+	}
+}
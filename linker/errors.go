@@ -1,16 +1,175 @@
 package linker
 
 import (
+	"reflect"
 	"strings"
 
+	ast3 "github.com/atmxlab/atmc/linker/ast"
+	ast2 "github.com/atmxlab/atmc/parser/ast"
 	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/atmxlab/atmc/types"
+	"github.com/samber/lo"
 )
 
 var (
-	ErrUnexpectedNodeType = errors.New("unexpected node type")
-	ErrNotFoundVariable   = errors.New("not found variable")
+	ErrUnexpectedNodeType  = errors.New("unexpected node type")
+	ErrNotFoundVariable    = errors.New("not found variable")
+	ErrMissingEnv          = errors.New("missing required env variable")
+	ErrInvalidEnv          = errors.New("invalid env variable value")
+	ErrInvalidOperands     = errors.New("invalid operands")
+	ErrDivisionByZero      = errors.New("division by zero")
+	ErrOverflow            = errors.New("overflow")
+	ErrInvalidCondition    = errors.New("invalid condition")
+	ErrUnknownFunction     = errors.New("unknown function")
+	ErrInvalidArguments    = errors.New("invalid arguments")
+	ErrCyclicDefinition    = errors.New("cyclic definition")
+	ErrCyclicReference     = errors.New("cyclic reference")
+	ErrInvalidMerge        = errors.New("invalid merge")
+	ErrDuplicateImportName = errors.New("duplicate import name")
+	ErrFunctionPanic       = errors.New("function panicked")
+	ErrInvalidResult       = errors.New("invalid function result")
 )
 
 func newErrNotFoundVariable(variable ...string) error {
 	return errors.Wrapf(ErrNotFoundVariable, "expected: %s", strings.Join(variable, "."))
 }
+
+func newErrMissingEnv(env ast2.Env, path string) error {
+	return errors.Wrapf(
+		ErrMissingEnv,
+		"%s at %s:%d:%d",
+		env.Name().String(),
+		path,
+		env.Location().Start().Line(),
+		env.Location().Start().Column(),
+	)
+}
+
+func newErrInvalidEnv(err error, env ast2.Env, path string) error {
+	return errors.Wrapf(
+		ErrInvalidEnv,
+		"cannot convert $%s to %s at %s:%d:%d: %s",
+		env.Name().String(),
+		env.Type(),
+		path,
+		env.Location().Start().Line(),
+		env.Location().Start().Column(),
+		err.Error(),
+	)
+}
+
+func newErrInvalidOperands(op ast2.Operator, left, right ast3.Expression) error {
+	return errors.Wrapf(ErrInvalidOperands, "cannot apply %s to %s and %s", op, typeName(left), typeName(right))
+}
+
+func newErrInvalidOperand(op ast2.Operator, operand ast3.Expression) error {
+	return errors.Wrapf(ErrInvalidOperands, "cannot apply %s to %s", op, typeName(operand))
+}
+
+func newErrEval(err error, b ast2.Binary, path string) error {
+	return errors.Wrapf(
+		err,
+		"evaluate %s at %s:%d:%d",
+		b.Operator(),
+		path,
+		b.OperatorPosition().Line(),
+		b.OperatorPosition().Column(),
+	)
+}
+
+func newErrInvalidCondition(cond ast3.Expression, c ast2.Conditional, path string) error {
+	return errors.Wrapf(
+		ErrInvalidCondition,
+		"expected bool, got %s at %s:%d:%d",
+		typeName(cond),
+		path,
+		c.Condition().Location().Start().Line(),
+		c.Condition().Location().Start().Column(),
+	)
+}
+
+func newErrInvalidArgument(i int, expected string, arg ast3.Expression) error {
+	return errors.Wrapf(ErrInvalidArguments, "argument %d: expected %s, got %s", i+1, expected, typeName(arg))
+}
+
+func newErrCall(err error, call ast2.Call, path string) error {
+	return errors.Wrapf(
+		err,
+		"call %s at %s:%d:%d",
+		call.Name().String(),
+		path,
+		call.Location().Start().Line(),
+		call.Location().Start().Column(),
+	)
+}
+
+func newErrInvalidGoValue(exp ast3.Expression, t reflect.Type) error {
+	return errors.Wrapf(ErrInvalidArguments, "cannot convert %s to %s", typeName(exp), t)
+}
+
+func newErrCycle(cycle []dependency, path string) error {
+	last := cycle[len(cycle)-1]
+
+	err := ErrCyclicReference
+	if last.definition {
+		err = ErrCyclicDefinition
+	}
+
+	return errors.Wrapf(
+		err,
+		"%s at %s:%d:%d",
+		strings.Join(lo.Map(cycle, func(item dependency, _ int) string { return item.name }), " -> "),
+		path,
+		last.loc.Start().Line(),
+		last.loc.Start().Column(),
+	)
+}
+
+func newErrSelfOutsideObject(v ast2.Var, path string) error {
+	return errors.Wrapf(
+		ErrNotFoundVariable,
+		"%s is used outside of an object at %s:%d:%d",
+		v.Path()[0].String(),
+		path,
+		v.Location().Start().Line(),
+		v.Location().Start().Column(),
+	)
+}
+
+func newErrRootNotObject(v ast2.Var, path string) error {
+	return errors.Wrapf(
+		ErrNotFoundVariable,
+		"%s: document root is not an object at %s:%d:%d",
+		v.String(),
+		path,
+		v.Location().Start().Line(),
+		v.Location().Start().Column(),
+	)
+}
+
+func newErrSelfReference(v ast2.Var, path string) error {
+	return errors.Wrapf(
+		ErrCyclicReference,
+		"%s refers to the object containing it at %s:%d:%d",
+		v.Path()[0].String(),
+		path,
+		v.Location().Start().Line(),
+		v.Location().Start().Column(),
+	)
+}
+
+func newErrInvalidMerge(merge ast3.MergeStrategy, existing, value ast3.Expression) error {
+	return errors.Wrapf(ErrInvalidMerge, "cannot apply %s to %s and %s", merge, typeName(existing), typeName(value))
+}
+
+// newErrMerge ошибка слияния ключа или spread-а: what - имя ключа или "common...".
+func newErrMerge(err error, what string, loc types.Location, path string) error {
+	return errors.Wrapf(
+		err,
+		"merge %s at %s:%d:%d",
+		what,
+		path,
+		loc.Start().Line(),
+		loc.Start().Column(),
+	)
+}
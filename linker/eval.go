@@ -0,0 +1,597 @@
+package linker
+
+import (
+	"math"
+	"math/bits"
+	"time"
+
+	ast3 "github.com/atmxlab/atmc/linker/ast"
+	ast2 "github.com/atmxlab/atmc/parser/ast"
+	"github.com/atmxlab/atmc/pkg/errors"
+)
+
+// linkBinary вычисляет бинарное выражение.
+// Логические операторы вычисляются лениво: правый операнд не линкуется, если результат уже известен.
+func (l *Linker) linkBinary(scp scope, b ast2.Binary) (ast3.Expression, error) {
+	left, err := l.linkExpression(scp, b.Left())
+	if err != nil {
+		return nil, errors.Wrap(err, "link left operand")
+	}
+
+	if b.Operator() == ast2.OpAnd || b.Operator() == ast2.OpOr {
+		leftBool, ok := left.(ast3.Bool)
+		if !ok {
+			return nil, newErrEval(newErrInvalidOperand(b.Operator(), left), b, scp.ast.Path())
+		}
+
+		if leftBool.Value() == (b.Operator() == ast2.OpOr) {
+			return leftBool, nil
+		}
+
+		right, err := l.linkExpression(scp, b.Right())
+		if err != nil {
+			return nil, errors.Wrap(err, "link right operand")
+		}
+
+		rightBool, ok := right.(ast3.Bool)
+		if !ok {
+			return nil, newErrEval(newErrInvalidOperand(b.Operator(), right), b, scp.ast.Path())
+		}
+
+		return rightBool, nil
+	}
+
+	right, err := l.linkExpression(scp, b.Right())
+	if err != nil {
+		return nil, errors.Wrap(err, "link right operand")
+	}
+
+	result, err := evalBinary(b.Operator(), left, right)
+	if err != nil {
+		return nil, newErrEval(err, b, scp.ast.Path())
+	}
+
+	return result, nil
+}
+
+// linkConditional вычисляет условное выражение.
+// Линкуется только выбранная ветка, поэтому в другой могут быть, например, незаданные обязательные env.
+func (l *Linker) linkConditional(scp scope, c ast2.Conditional) (ast3.Expression, error) {
+	cond, err := l.linkExpression(scp, c.Condition())
+	if err != nil {
+		return nil, errors.Wrap(err, "link condition")
+	}
+
+	condBool, ok := cond.(ast3.Bool)
+	if !ok {
+		return nil, newErrInvalidCondition(cond, c, scp.ast.Path())
+	}
+
+	if condBool.Value() {
+		exp, err := l.linkExpression(scp, c.Then())
+		if err != nil {
+			return nil, errors.Wrap(err, "link then branch")
+		}
+
+		return exp, nil
+	}
+
+	exp, err := l.linkExpression(scp, c.Else())
+	if err != nil {
+		return nil, errors.Wrap(err, "link else branch")
+	}
+
+	return exp, nil
+}
+
+func (l *Linker) linkUnary(scp scope, u ast2.Unary) (ast3.Expression, error) {
+	operand, err := l.linkExpression(scp, u.Operand())
+	if err != nil {
+		return nil, errors.Wrap(err, "link operand")
+	}
+
+	result, err := evalUnary(u.Operator(), operand)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"evaluate %s at %s:%d:%d",
+			u.Operator(),
+			scp.ast.Path(),
+			u.Location().Start().Line(),
+			u.Location().Start().Column(),
+		)
+	}
+
+	return result, nil
+}
+
+func evalUnary(op ast2.Operator, operand ast3.Expression) (ast3.Expression, error) {
+	switch v := operand.(type) {
+	case ast3.Bool:
+		if op == ast2.OpNot {
+			return ast3.NewBool(!v.Value()), nil
+		}
+	case ast3.Int:
+		if op == ast2.OpSub {
+			i, err := subInt(0, v.Value())
+			if err != nil {
+				return nil, err
+			}
+
+			return ast3.NewInt(i), nil
+		}
+	case ast3.Float:
+		if op == ast2.OpSub {
+			return ast3.NewFloat(-v.Value()), nil
+		}
+	case ast3.Duration:
+		if op == ast2.OpSub {
+			i, err := subInt(0, int64(v.Value()))
+			if err != nil {
+				return nil, err
+			}
+
+			return ast3.NewDuration(time.Duration(i)), nil
+		}
+	}
+
+	return nil, newErrInvalidOperand(op, operand)
+}
+
+func evalBinary(op ast2.Operator, left, right ast3.Expression) (ast3.Expression, error) {
+	switch op {
+	case ast2.OpEq:
+		return ast3.NewBool(equal(removeDeleted(left), removeDeleted(right))), nil
+	case ast2.OpNotEq:
+		return ast3.NewBool(!equal(removeDeleted(left), removeDeleted(right))), nil
+	case ast2.OpLess, ast2.OpLessEq, ast2.OpGreater, ast2.OpGreaterEq:
+		return evalComparison(op, left, right)
+	default:
+		return evalArithmetic(op, left, right)
+	}
+}
+
+// equal сравнивает значения на равенство по типам. Int и Float сравниваются как числа, как и в арифметике,
+// массивы - поэлементно, объекты - по ключам без учета порядка. Значения разных типов не равны.
+func equal(left, right ast3.Expression) bool {
+	switch l := left.(type) {
+	case ast3.Int:
+		if r, ok := right.(ast3.Int); ok {
+			return l.Value() == r.Value()
+		}
+	case ast3.String:
+		r, ok := right.(ast3.String)
+		return ok && l.Value() == r.Value()
+	case ast3.Bool:
+		r, ok := right.(ast3.Bool)
+		return ok && l.Value() == r.Value()
+	case ast3.Duration:
+		r, ok := right.(ast3.Duration)
+		return ok && l.Value() == r.Value()
+	case ast3.ByteSize:
+		r, ok := right.(ast3.ByteSize)
+		return ok && l.Value() == r.Value()
+	case ast3.Null:
+		_, ok := right.(ast3.Null)
+		return ok
+	case ast3.Array:
+		r, ok := right.(ast3.Array)
+		return ok && equalArrays(l, r)
+	case ast3.Object:
+		r, ok := right.(ast3.Object)
+		return ok && equalObjects(l, r)
+	}
+
+	if l, r, ok := asFloats(left, right); ok {
+		return l == r
+	}
+
+	return false
+}
+
+func equalArrays(left, right ast3.Array) bool {
+	if len(left.Elements()) != len(right.Elements()) {
+		return false
+	}
+
+	for i, elem := range left.Elements() {
+		if !equal(elem, right.Elements()[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalObjects(left, right ast3.Object) bool {
+	if len(left.KV()) != len(right.KV()) {
+		return false
+	}
+
+	values := make(map[string]ast3.Expression, len(right.KV()))
+	for _, kv := range right.KV() {
+		values[kv.Key().String()] = kv.Value()
+	}
+
+	for _, kv := range left.KV() {
+		value, ok := values[kv.Key().String()]
+		if !ok || !equal(kv.Value(), value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func evalComparison(op ast2.Operator, left, right ast3.Expression) (ast3.Expression, error) {
+	cmp, ok := compare(left, right)
+	if !ok {
+		return nil, newErrInvalidOperands(op, left, right)
+	}
+
+	switch op {
+	case ast2.OpLess:
+		return ast3.NewBool(cmp < 0), nil
+	case ast2.OpLessEq:
+		return ast3.NewBool(cmp <= 0), nil
+	case ast2.OpGreater:
+		return ast3.NewBool(cmp > 0), nil
+	default:
+		return ast3.NewBool(cmp >= 0), nil
+	}
+}
+
+// compare упорядочивает числа, строки, длительности и размеры.
+func compare(left, right ast3.Expression) (int, bool) {
+	switch l := left.(type) {
+	case ast3.Int:
+		if r, ok := right.(ast3.Int); ok {
+			return cmpValues(l.Value(), r.Value()), true
+		}
+	case ast3.String:
+		if r, ok := right.(ast3.String); ok {
+			return cmpValues(l.Value(), r.Value()), true
+		}
+	case ast3.Duration:
+		if r, ok := right.(ast3.Duration); ok {
+			return cmpValues(l.Value(), r.Value()), true
+		}
+	case ast3.ByteSize:
+		if r, ok := right.(ast3.ByteSize); ok {
+			return cmpValues(l.Value(), r.Value()), true
+		}
+	}
+
+	if l, r, ok := asFloats(left, right); ok {
+		return cmpValues(l, r), true
+	}
+
+	return 0, false
+}
+
+func cmpValues[T int64 | uint64 | float64 | string | time.Duration](l, r T) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// asFloats приводит пару чисел (Int или Float) к float64.
+func asFloats(left, right ast3.Expression) (float64, float64, bool) {
+	l, ok := asFloat(left)
+	if !ok {
+		return 0, 0, false
+	}
+
+	r, ok := asFloat(right)
+	if !ok {
+		return 0, 0, false
+	}
+
+	return l, r, true
+}
+
+func asFloat(exp ast3.Expression) (float64, bool) {
+	switch v := exp.(type) {
+	case ast3.Int:
+		return float64(v.Value()), true
+	case ast3.Float:
+		return v.Value(), true
+	default:
+		return 0, false
+	}
+}
+
+func evalArithmetic(op ast2.Operator, left, right ast3.Expression) (ast3.Expression, error) {
+	switch l := left.(type) {
+	case ast3.Int:
+		switch r := right.(type) {
+		case ast3.Int:
+			i, err := evalInt(op, l.Value(), r.Value())
+			if err != nil {
+				return nil, err
+			}
+
+			return ast3.NewInt(i), nil
+		case ast3.Duration, ast3.ByteSize:
+			// Умножение коммутативно: 3 * 1s == 1s * 3.
+			if op == ast2.OpMul {
+				return evalArithmetic(op, right, left)
+			}
+		}
+	case ast3.Float:
+		switch right.(type) {
+		case ast3.Duration, ast3.ByteSize:
+			if op == ast2.OpMul {
+				return evalArithmetic(op, right, left)
+			}
+		}
+	case ast3.String:
+		if r, ok := right.(ast3.String); ok && op == ast2.OpAdd {
+			return ast3.NewString(l.Value() + r.Value()), nil
+		}
+	case ast3.Duration:
+		d, err := evalDuration(op, l, right)
+		if err != nil {
+			return nil, err
+		}
+
+		if d != nil {
+			return d, nil
+		}
+	case ast3.ByteSize:
+		b, err := evalByteSize(op, l, right)
+		if err != nil {
+			return nil, err
+		}
+
+		if b != nil {
+			return b, nil
+		}
+	}
+
+	if l, r, ok := asFloats(left, right); ok {
+		f, err := evalFloat(op, l, r)
+		if err != nil {
+			return nil, err
+		}
+
+		if f != nil {
+			return f, nil
+		}
+	}
+
+	return nil, newErrInvalidOperands(op, left, right)
+}
+
+// evalDuration: длительности складываются и вычитаются между собой, умножаются и делятся на числа.
+// Возвращает nil, если операция для таких операндов не определена.
+func evalDuration(op ast2.Operator, left ast3.Duration, right ast3.Expression) (ast3.Expression, error) {
+	switch r := right.(type) {
+	case ast3.Duration:
+		if op != ast2.OpAdd && op != ast2.OpSub {
+			return nil, nil
+		}
+
+		i, err := evalInt(op, int64(left.Value()), int64(r.Value()))
+		if err != nil {
+			return nil, err
+		}
+
+		return ast3.NewDuration(time.Duration(i)), nil
+	case ast3.Int:
+		if op != ast2.OpMul && op != ast2.OpDiv {
+			return nil, nil
+		}
+
+		i, err := evalInt(op, int64(left.Value()), r.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		return ast3.NewDuration(time.Duration(i)), nil
+	case ast3.Float:
+		if op != ast2.OpMul && op != ast2.OpDiv {
+			return nil, nil
+		}
+
+		f, err := evalFloat(op, float64(left.Value()), r.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		v := math.Round(f.(ast3.Float).Value())
+		if v > math.MaxInt64 || v < math.MinInt64 {
+			return nil, errors.Wrap(ErrOverflow, "duration out of range")
+		}
+
+		return ast3.NewDuration(time.Duration(v)), nil
+	default:
+		return nil, nil
+	}
+}
+
+// evalByteSize: размеры складываются и вычитаются между собой, умножаются и делятся на числа.
+// Возвращает nil, если операция для таких операндов не определена.
+func evalByteSize(op ast2.Operator, left ast3.ByteSize, right ast3.Expression) (ast3.Expression, error) {
+	switch r := right.(type) {
+	case ast3.ByteSize:
+		switch op {
+		case ast2.OpAdd:
+			sum, carry := bits.Add64(left.Value(), r.Value(), 0)
+			if carry != 0 {
+				return nil, errors.Wrap(ErrOverflow, "byte size out of range")
+			}
+
+			return ast3.NewByteSize(sum), nil
+		case ast2.OpSub:
+			if left.Value() < r.Value() {
+				return nil, errors.Wrap(ErrOverflow, "negative byte size")
+			}
+
+			return ast3.NewByteSize(left.Value() - r.Value()), nil
+		default:
+			return nil, nil
+		}
+	case ast3.Int:
+		if r.Value() < 0 {
+			return nil, errors.Wrap(ErrOverflow, "negative byte size")
+		}
+
+		switch op {
+		case ast2.OpMul:
+			hi, lo := bits.Mul64(left.Value(), uint64(r.Value()))
+			if hi != 0 {
+				return nil, errors.Wrap(ErrOverflow, "byte size out of range")
+			}
+
+			return ast3.NewByteSize(lo), nil
+		case ast2.OpDiv:
+			if r.Value() == 0 {
+				return nil, ErrDivisionByZero
+			}
+
+			return ast3.NewByteSize(left.Value() / uint64(r.Value())), nil
+		default:
+			return nil, nil
+		}
+	case ast3.Float:
+		if op != ast2.OpMul && op != ast2.OpDiv {
+			return nil, nil
+		}
+
+		f, err := evalFloat(op, float64(left.Value()), r.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		v := math.Round(f.(ast3.Float).Value())
+		if v < 0 || v >= math.MaxUint64 {
+			return nil, errors.Wrap(ErrOverflow, "byte size out of range")
+		}
+
+		return ast3.NewByteSize(uint64(v)), nil
+	default:
+		return nil, nil
+	}
+}
+
+// evalFloat возвращает nil, если операция для чисел с плавающей точкой не определена.
+func evalFloat(op ast2.Operator, l, r float64) (ast3.Expression, error) {
+	var result float64
+
+	switch op {
+	case ast2.OpAdd:
+		result = l + r
+	case ast2.OpSub:
+		result = l - r
+	case ast2.OpMul:
+		result = l * r
+	case ast2.OpDiv:
+		if r == 0 {
+			return nil, ErrDivisionByZero
+		}
+
+		result = l / r
+	default:
+		return nil, nil
+	}
+
+	if math.IsInf(result, 0) {
+		return nil, errors.Wrap(ErrOverflow, "float out of range")
+	}
+
+	return ast3.NewFloat(result), nil
+}
+
+func evalInt(op ast2.Operator, l, r int64) (int64, error) {
+	switch op {
+	case ast2.OpAdd:
+		return addInt(l, r)
+	case ast2.OpSub:
+		return subInt(l, r)
+	case ast2.OpMul:
+		return mulInt(l, r)
+	case ast2.OpDiv:
+		if r == 0 {
+			return 0, ErrDivisionByZero
+		}
+
+		if l == math.MinInt64 && r == -1 {
+			return 0, errors.Wrap(ErrOverflow, "integer out of range")
+		}
+
+		return l / r, nil
+	case ast2.OpMod:
+		if r == 0 {
+			return 0, ErrDivisionByZero
+		}
+
+		if r == -1 {
+			return 0, nil
+		}
+
+		return l % r, nil
+	default:
+		return 0, errors.Newf("unknown operator %s", op)
+	}
+}
+
+func addInt(l, r int64) (int64, error) {
+	if (r > 0 && l > math.MaxInt64-r) || (r < 0 && l < math.MinInt64-r) {
+		return 0, errors.Wrap(ErrOverflow, "integer out of range")
+	}
+
+	return l + r, nil
+}
+
+func subInt(l, r int64) (int64, error) {
+	if (r < 0 && l > math.MaxInt64+r) || (r > 0 && l < math.MinInt64+r) {
+		return 0, errors.Wrap(ErrOverflow, "integer out of range")
+	}
+
+	return l - r, nil
+}
+
+func mulInt(l, r int64) (int64, error) {
+	if l == 0 || r == 0 {
+		return 0, nil
+	}
+
+	result := l * r
+	if result/r != l || (l == -1 && r == math.MinInt64) || (r == -1 && l == math.MinInt64) {
+		return 0, errors.Wrap(ErrOverflow, "integer out of range")
+	}
+
+	return result, nil
+}
+
+// typeName - название типа значения для сообщений об ошибках.
+func typeName(exp ast3.Expression) string {
+	switch exp.(type) {
+	case ast3.Int:
+		return "int"
+	case ast3.Float:
+		return "float"
+	case ast3.String:
+		return "string"
+	case ast3.Bool:
+		return "bool"
+	case ast3.Duration:
+		return "duration"
+	case ast3.ByteSize:
+		return "byte size"
+	case ast3.Null:
+		return "null"
+	case ast3.Object:
+		return "object"
+	case ast3.Array:
+		return "array"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,377 @@
+package linker
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	ast3 "github.com/atmxlab/atmc/linker/ast"
+	ast2 "github.com/atmxlab/atmc/parser/ast"
+	"github.com/atmxlab/atmc/pkg/errors"
+)
+
+// FS доступ к файловой системе для функции file().
+type FS interface {
+	ReadFile(string) ([]byte, error)
+	AbsPath(baseDir, relPath string) (string, error)
+}
+
+// builtin встроенная функция. Аргументы уже слинкованы.
+type builtin func(l *Linker, scp scope, args []ast3.Expression) (ast3.Expression, error)
+
+var builtins = map[string]builtin{
+	"upper":   stringFunc(strings.ToUpper),
+	"lower":   stringFunc(strings.ToLower),
+	"trim":    builtinTrim,
+	"replace": builtinReplace,
+	"split":   builtinSplit,
+	"join":    builtinJoin,
+	"len":     builtinLen,
+	"concat":  builtinConcat,
+	"keys":    builtinKeys,
+	"values":  builtinValues,
+	"merge":   builtinMerge,
+	"base64":  stringFunc(func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }),
+	"json":    builtinJSON,
+	"sha256":  stringFunc(sha256Hex),
+	"file":    builtinFile,
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsBuiltinFunction сообщает, есть ли встроенная функция с таким именем.
+func IsBuiltinFunction(name string) bool {
+	_, ok := builtins[name]
+	return ok
+}
+
+// FunctionNames возвращает имена встроенных и пользовательских функций.
+func (l *Linker) FunctionNames() []string {
+	names := make([]string, 0, len(builtins)+len(l.functions))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	for name := range l.functions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func (l *Linker) linkCall(scp scope, call ast2.Call) (ast3.Expression, error) {
+	fn, ok := builtins[call.Name().String()]
+	if !ok {
+		userFn, ok := l.functions[call.Name().String()]
+		if !ok {
+			return nil, newErrCall(errors.Wrap(ErrUnknownFunction, call.Name().String()), call, scp.ast.Path())
+		}
+
+		fn = func(_ *Linker, _ scope, args []ast3.Expression) (ast3.Expression, error) {
+			return userFn.call(args)
+		}
+	}
+
+	args := make([]ast3.Expression, 0, len(call.Args()))
+	for i, arg := range call.Args() {
+		exp, err := l.linkExpression(scp, arg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "link argument %d", i+1)
+		}
+
+		// Функции получают итоговые значения - без маркеров удаления и стратегий слияния.
+		args = append(args, removeDeleted(exp))
+	}
+
+	result, err := fn(l, scp, args)
+	if err != nil {
+		return nil, newErrCall(err, call, scp.ast.Path())
+	}
+
+	return result, nil
+}
+
+func stringFunc(f func(string) string) builtin {
+	return func(_ *Linker, _ scope, args []ast3.Expression) (ast3.Expression, error) {
+		if err := checkArgsCount(args, 1, 1); err != nil {
+			return nil, err
+		}
+
+		s, err := argAs[ast3.String](args, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		return ast3.NewString(f(s.Value())), nil
+	}
+}
+
+// builtinTrim убирает пробельные символы по краям строки, а со вторым аргументом - символы из него.
+func builtinTrim(_ *Linker, _ scope, args []ast3.Expression) (ast3.Expression, error) {
+	if err := checkArgsCount(args, 1, 2); err != nil {
+		return nil, err
+	}
+
+	s, err := argAs[ast3.String](args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) == 1 {
+		return ast3.NewString(strings.TrimSpace(s.Value())), nil
+	}
+
+	cutset, err := argAs[ast3.String](args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast3.NewString(strings.Trim(s.Value(), cutset.Value())), nil
+}
+
+func builtinReplace(_ *Linker, _ scope, args []ast3.Expression) (ast3.Expression, error) {
+	if err := checkArgsCount(args, 3, 3); err != nil {
+		return nil, err
+	}
+
+	strs, err := stringArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast3.NewString(strings.ReplaceAll(strs[0], strs[1], strs[2])), nil
+}
+
+func builtinSplit(_ *Linker, _ scope, args []ast3.Expression) (ast3.Expression, error) {
+	if err := checkArgsCount(args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	strs, err := stringArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(strs[0], strs[1])
+	elems := make([]ast3.Expression, 0, len(parts))
+	for _, part := range parts {
+		elems = append(elems, ast3.NewString(part))
+	}
+
+	return ast3.NewArray(elems), nil
+}
+
+// builtinJoin склеивает элементы массива. Элементы - любые скалярные значения, как в интерполяции.
+func builtinJoin(_ *Linker, _ scope, args []ast3.Expression) (ast3.Expression, error) {
+	if err := checkArgsCount(args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	arr, err := argAs[ast3.Array](args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	sep, err := argAs[ast3.String](args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]string, 0, len(arr.Elements()))
+	for i, elem := range arr.Elements() {
+		str, err := interpolate(elem)
+		if err != nil {
+			return nil, errors.Wrapf(err, "element %d", i)
+		}
+
+		parts = append(parts, str)
+	}
+
+	return ast3.NewString(strings.Join(parts, sep.Value())), nil
+}
+
+// builtinLen возвращает количество символов строки, элементов массива или ключей объекта.
+func builtinLen(_ *Linker, _ scope, args []ast3.Expression) (ast3.Expression, error) {
+	if err := checkArgsCount(args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	switch v := args[0].(type) {
+	case ast3.String:
+		return ast3.NewInt(int64(utf8.RuneCountInString(v.Value()))), nil
+	case ast3.Array:
+		return ast3.NewInt(int64(len(v.Elements()))), nil
+	case ast3.Object:
+		return ast3.NewInt(int64(len(v.KV()))), nil
+	default:
+		return nil, newErrInvalidArgument(0, "string, array or object", args[0])
+	}
+}
+
+// builtinConcat склеивает массивы или строки.
+func builtinConcat(_ *Linker, _ scope, args []ast3.Expression) (ast3.Expression, error) {
+	if err := checkArgsCount(args, 1, -1); err != nil {
+		return nil, err
+	}
+
+	if _, ok := args[0].(ast3.String); ok {
+		strs, err := stringArgs(args)
+		if err != nil {
+			return nil, err
+		}
+
+		return ast3.NewString(strings.Join(strs, "")), nil
+	}
+
+	elems := make([]ast3.Expression, 0)
+	for i := range args {
+		arr, err := argAs[ast3.Array](args, i)
+		if err != nil {
+			return nil, err
+		}
+
+		elems = append(elems, arr.Elements()...)
+	}
+
+	return ast3.NewArray(elems), nil
+}
+
+func builtinKeys(_ *Linker, _ scope, args []ast3.Expression) (ast3.Expression, error) {
+	if err := checkArgsCount(args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	obj, err := argAs[ast3.Object](args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]ast3.Expression, 0, len(obj.KV()))
+	for _, kv := range obj.KV() {
+		keys = append(keys, ast3.NewString(kv.Key().String()))
+	}
+
+	return ast3.NewArray(keys), nil
+}
+
+func builtinValues(_ *Linker, _ scope, args []ast3.Expression) (ast3.Expression, error) {
+	if err := checkArgsCount(args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	obj, err := argAs[ast3.Object](args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]ast3.Expression, 0, len(obj.KV()))
+	for _, kv := range obj.KV() {
+		values = append(values, kv.Value())
+	}
+
+	return ast3.NewArray(values), nil
+}
+
+// builtinMerge рекурсивно сливает объекты слева направо - как повторное объявление ключа.
+func builtinMerge(l *Linker, _ scope, args []ast3.Expression) (ast3.Expression, error) {
+	merged := ast3.NewKV(ast3.NewIdent(""), ast3.NewObject([]ast3.KV{}))
+
+	for i := range args {
+		obj, err := argAs[ast3.Object](args, i)
+		if err != nil {
+			return nil, err
+		}
+
+		merged, err = l.mergeEntries(merged, ast3.NewKV(merged.Key(), obj))
+		if err != nil {
+			return nil, errors.Wrapf(err, "argument %d", i+1)
+		}
+	}
+
+	return merged.Value(), nil
+}
+
+func builtinJSON(_ *Linker, _ scope, args []ast3.Expression) (ast3.Expression, error) {
+	if err := checkArgsCount(args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	data, err := ast3.EncodeJSON(args[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "encode json")
+	}
+
+	return ast3.NewString(string(data)), nil
+}
+
+// builtinFile читает файл. Относительный путь считается от директории текущего конфига.
+func builtinFile(l *Linker, scp scope, args []ast3.Expression) (ast3.Expression, error) {
+	if err := checkArgsCount(args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	path, err := argAs[ast3.String](args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.fs == nil {
+		return nil, errors.New("file system is not available")
+	}
+
+	absPath, err := l.fs.AbsPath(filepath.Dir(scp.ast.Path()), path.Value())
+	if err != nil {
+		return nil, errors.Wrap(err, "get abs path")
+	}
+
+	content, err := l.fs.ReadFile(absPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read file %s", absPath)
+	}
+
+	return ast3.NewString(string(content)), nil
+}
+
+// checkArgsCount проверяет количество аргументов. max < 0 - без ограничения.
+func checkArgsCount(args []ast3.Expression, min, max int) error {
+	switch {
+	case len(args) < min:
+		return errors.Wrapf(ErrInvalidArguments, "expected at least %d arguments, got %d", min, len(args))
+	case max >= 0 && len(args) > max:
+		return errors.Wrapf(ErrInvalidArguments, "expected at most %d arguments, got %d", max, len(args))
+	default:
+		return nil
+	}
+}
+
+func argAs[T ast3.Expression](args []ast3.Expression, i int) (T, error) {
+	v, ok := args[i].(T)
+	if !ok {
+		var zero T
+		return zero, newErrInvalidArgument(i, typeName(zero), args[i])
+	}
+
+	return v, nil
+}
+
+func stringArgs(args []ast3.Expression) ([]string, error) {
+	strs := make([]string, 0, len(args))
+	for i := range args {
+		s, err := argAs[ast3.String](args, i)
+		if err != nil {
+			return nil, err
+		}
+
+		strs = append(strs, s.Value())
+	}
+
+	return strs, nil
+}
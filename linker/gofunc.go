@@ -0,0 +1,403 @@
+package linker
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"time"
+
+	ast3 "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/pkg/errors"
+)
+
+var (
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	anyType      = reflect.TypeOf((*any)(nil)).Elem()
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Function пользовательская Go-функция, доступная в конфигах.
+// Аргументы и результат преобразуются между значениями linker/ast и Go:
+// string, bool, целые и вещественные числа, time.Duration, слайсы, map[string]T, указатели и any.
+type Function struct {
+	fn reflect.Value
+}
+
+// NewFunction проверяет сигнатуру функции. Функция возвращает значение или значение и ошибку.
+func NewFunction(fn any) (Function, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return Function{}, errors.Newf("expected function, got %T", fn)
+	}
+
+	typ := v.Type()
+
+	for i := 0; i < typ.NumIn(); i++ {
+		in := typ.In(i)
+		if typ.IsVariadic() && i == typ.NumIn()-1 {
+			in = in.Elem()
+		}
+
+		if !isSupportedType(in) {
+			return Function{}, errors.Newf("unsupported argument %d type %s", i+1, in)
+		}
+	}
+
+	switch {
+	case typ.NumOut() == 1 && typ.Out(0) != errorType:
+	case typ.NumOut() == 2 && typ.Out(1) == errorType:
+	default:
+		return Function{}, errors.Newf("expected function returning value or value and error, got %s", typ)
+	}
+
+	if !isSupportedType(typ.Out(0)) {
+		return Function{}, errors.Newf("unsupported result type %s", typ.Out(0))
+	}
+
+	return Function{fn: v}, nil
+}
+
+// call вызывает функцию. Паника в функции возвращается ошибкой, а не роняет загрузку конфига.
+func (f Function) call(args []ast3.Expression) (result ast3.Expression, err error) {
+	typ := f.fn.Type()
+
+	min := typ.NumIn()
+	max := typ.NumIn()
+	if typ.IsVariadic() {
+		min--
+		max = -1
+	}
+
+	if err := checkArgsCount(args, min, max); err != nil {
+		return nil, err
+	}
+
+	in := make([]reflect.Value, 0, len(args))
+	for i, arg := range args {
+		var argType reflect.Type
+		if typ.IsVariadic() && i >= typ.NumIn()-1 {
+			argType = typ.In(typ.NumIn() - 1).Elem()
+		} else {
+			argType = typ.In(i)
+		}
+
+		v, err := toGo(arg, argType)
+		if err != nil {
+			return nil, errors.Wrapf(err, "argument %d", i+1)
+		}
+
+		in = append(in, v)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, errors.Wrapf(ErrFunctionPanic, "%v", r)
+		}
+	}()
+
+	out := f.fn.Call(in)
+	if len(out) == 2 && !out[1].IsNil() {
+		return nil, out[1].Interface().(error)
+	}
+
+	result, err = fromGo(out[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "result")
+	}
+
+	return result, nil
+}
+
+func isSupportedType(t reflect.Type) bool {
+	if t == durationType || t == anyType {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice, reflect.Pointer:
+		return isSupportedType(t.Elem())
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && isSupportedType(t.Elem())
+	default:
+		return false
+	}
+}
+
+// toGo преобразует значение конфига в значение Go типа t.
+func toGo(exp ast3.Expression, t reflect.Type) (reflect.Value, error) {
+	if _, ok := exp.(ast3.Null); ok {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+			return reflect.Zero(t), nil
+		}
+	}
+
+	if t == anyType {
+		v, err := toAny(exp)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if v == nil {
+			return reflect.Zero(t), nil
+		}
+
+		return reflect.ValueOf(v), nil
+	}
+
+	if t == durationType {
+		d, ok := exp.(ast3.Duration)
+		if !ok {
+			return reflect.Value{}, newErrInvalidGoValue(exp, t)
+		}
+
+		return reflect.ValueOf(d.Value()), nil
+	}
+
+	v := reflect.New(t).Elem()
+
+	switch t.Kind() {
+	case reflect.String:
+		s, ok := exp.(ast3.String)
+		if !ok {
+			return reflect.Value{}, newErrInvalidGoValue(exp, t)
+		}
+
+		v.SetString(s.Value())
+	case reflect.Bool:
+		b, ok := exp.(ast3.Bool)
+		if !ok {
+			return reflect.Value{}, newErrInvalidGoValue(exp, t)
+		}
+
+		v.SetBool(b.Value())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch n := exp.(type) {
+		case ast3.Int:
+			i = n.Value()
+		case ast3.ByteSize:
+			if n.Value() > math.MaxInt64 {
+				return reflect.Value{}, errors.Wrapf(ErrOverflow, "%d overflows %s", n.Value(), t)
+			}
+
+			i = int64(n.Value())
+		default:
+			return reflect.Value{}, newErrInvalidGoValue(exp, t)
+		}
+
+		if v.OverflowInt(i) {
+			return reflect.Value{}, errors.Wrapf(ErrOverflow, "%d overflows %s", i, t)
+		}
+
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		switch n := exp.(type) {
+		case ast3.Int:
+			if n.Value() < 0 {
+				return reflect.Value{}, errors.Wrapf(ErrOverflow, "%d overflows %s", n.Value(), t)
+			}
+
+			u = uint64(n.Value())
+		case ast3.ByteSize:
+			u = n.Value()
+		default:
+			return reflect.Value{}, newErrInvalidGoValue(exp, t)
+		}
+
+		if v.OverflowUint(u) {
+			return reflect.Value{}, errors.Wrapf(ErrOverflow, "%d overflows %s", u, t)
+		}
+
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, ok := asFloat(exp)
+		if !ok {
+			return reflect.Value{}, newErrInvalidGoValue(exp, t)
+		}
+
+		if v.OverflowFloat(f) {
+			return reflect.Value{}, errors.Wrapf(ErrOverflow, "%g overflows %s", f, t)
+		}
+
+		v.SetFloat(f)
+	case reflect.Slice:
+		arr, ok := exp.(ast3.Array)
+		if !ok {
+			return reflect.Value{}, newErrInvalidGoValue(exp, t)
+		}
+
+		v = reflect.MakeSlice(t, 0, len(arr.Elements()))
+		for i, elem := range arr.Elements() {
+			e, err := toGo(elem, t.Elem())
+			if err != nil {
+				return reflect.Value{}, errors.Wrapf(err, "element %d", i)
+			}
+
+			v = reflect.Append(v, e)
+		}
+	case reflect.Map:
+		obj, ok := exp.(ast3.Object)
+		if !ok {
+			return reflect.Value{}, newErrInvalidGoValue(exp, t)
+		}
+
+		v = reflect.MakeMapWithSize(t, len(obj.KV()))
+		for _, kv := range obj.KV() {
+			e, err := toGo(kv.Value(), t.Elem())
+			if err != nil {
+				return reflect.Value{}, errors.Wrapf(err, "key %s", kv.Key().String())
+			}
+
+			v.SetMapIndex(reflect.ValueOf(kv.Key().String()).Convert(t.Key()), e)
+		}
+	case reflect.Pointer:
+		e, err := toGo(exp, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		v = reflect.New(t.Elem())
+		v.Elem().Set(e)
+	default:
+		return reflect.Value{}, newErrInvalidGoValue(exp, t)
+	}
+
+	return v, nil
+}
+
+// toAny преобразует значение конфига так же, как MapCompiler.
+func toAny(exp ast3.Expression) (any, error) {
+	switch v := exp.(type) {
+	case ast3.Object:
+		m := make(map[string]any, len(v.KV()))
+		for _, kv := range v.KV() {
+			e, err := toAny(kv.Value())
+			if err != nil {
+				return nil, err
+			}
+
+			m[kv.Key().String()] = e
+		}
+
+		return m, nil
+	case ast3.Array:
+		s := make([]any, 0, len(v.Elements()))
+		for _, elem := range v.Elements() {
+			e, err := toAny(elem)
+			if err != nil {
+				return nil, err
+			}
+
+			s = append(s, e)
+		}
+
+		return s, nil
+	case ast3.String:
+		return v.Value(), nil
+	case ast3.Bool:
+		return v.Value(), nil
+	case ast3.Int:
+		return v.Value(), nil
+	case ast3.Float:
+		return v.Value(), nil
+	case ast3.Duration:
+		return v.Value(), nil
+	case ast3.ByteSize:
+		return v.Value(), nil
+	case ast3.Null:
+		return nil, nil
+	default:
+		return nil, errors.Wrapf(ErrUnexpectedNodeType, "%T", exp)
+	}
+}
+
+// fromGo преобразует результат Go-функции в значение конфига. Ключи map сортируются.
+func fromGo(v reflect.Value) (ast3.Expression, error) {
+	if !v.IsValid() {
+		return ast3.NewNull(), nil
+	}
+
+	if v.Type() == durationType {
+		return ast3.NewDuration(time.Duration(v.Int())), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return ast3.NewString(v.String()), nil
+	case reflect.Bool:
+		return ast3.NewBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ast3.NewInt(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt64 {
+			return nil, errors.Wrapf(ErrOverflow, "%d overflows int", v.Uint())
+		}
+
+		return ast3.NewInt(int64(v.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		// Для бесконечности и NaN нет значений ни в конфиге, ни в JSON.
+		if math.IsInf(v.Float(), 0) || math.IsNaN(v.Float()) {
+			return nil, errors.Wrapf(ErrInvalidResult, "unsupported float %g", v.Float())
+		}
+
+		return ast3.NewFloat(v.Float()), nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return ast3.NewNull(), nil
+		}
+
+		elems := make([]ast3.Expression, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			e, err := fromGo(v.Index(i))
+			if err != nil {
+				return nil, errors.Wrapf(err, "element %d", i)
+			}
+
+			elems = append(elems, e)
+		}
+
+		return ast3.NewArray(elems), nil
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, errors.Newf("unsupported map key type %s", v.Type().Key())
+		}
+
+		if v.IsNil() {
+			return ast3.NewNull(), nil
+		}
+
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, key.String())
+		}
+
+		sort.Strings(keys)
+
+		kvs := make([]ast3.KV, 0, len(keys))
+		for _, key := range keys {
+			e, err := fromGo(v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key())))
+			if err != nil {
+				return nil, errors.Wrapf(err, "key %s", key)
+			}
+
+			kvs = append(kvs, ast3.NewKV(ast3.NewIdent(key), e))
+		}
+
+		return ast3.NewObject(kvs), nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return ast3.NewNull(), nil
+		}
+
+		return fromGo(v.Elem())
+	default:
+		return nil, errors.Newf("unsupported value type %s", v.Type())
+	}
+}
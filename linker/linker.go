@@ -1,10 +1,16 @@
 package linker
 
 import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
 	ast3 "github.com/atmxlab/atmc/linker/ast"
 	ast2 "github.com/atmxlab/atmc/parser/ast"
 	"github.com/atmxlab/atmc/pkg/errors"
 	"github.com/atmxlab/atmc/pkg/orderedset"
+	"github.com/atmxlab/atmc/types"
 	"github.com/samber/lo"
 )
 
@@ -15,26 +21,67 @@ type Linker struct {
 	linkedByPath map[string]ast3.Ast
 	// Необходим, чтобы резолвить переменные среды.
 	env map[string]string
+	// Незаданные обязательные переменные среды.
+	missingEnv []error
+	// Необходим функции file().
+	fs FS
+	// Пользовательские функции.
+	functions map[string]Function
+}
+
+type Option func(*Linker)
+
+// WithFunction регистрирует пользовательскую функцию.
+func WithFunction(name string, fn Function) Option {
+	return func(l *Linker) {
+		l.functions[name] = fn
+	}
 }
 
-func New() *Linker {
-	return &Linker{
+func New(opts ...Option) *Linker {
+	l := &Linker{
 		astByPath:    make(map[string]ast2.WithPath),
 		linkedByPath: make(map[string]ast3.Ast),
 		env:          make(map[string]string),
+		functions:    make(map[string]Function),
+	}
+	for _, opt := range opts {
+		opt(l)
 	}
+
+	return l
 }
 
 type scope struct {
 	// Необходим, чтобы добираться до внутренностей переменных по названию.
 	linkedByName map[string]ast3.Ast
 	ast          ast2.WithPath
+	// Локальные переменные файла. Линкуются лениво - при первом обращении.
+	definitions map[string]ast2.Definition
+	linkedDefs  map[string]ast3.Expression
+	// Уже слинкованные ключи документа по позиции ключа - на них можно ссылаться через self и root.
+	linkedKVs map[types.Position]ast3.KV
+	// Локальные переменные и ключи, которые сейчас линкуются, - для поиска циклов.
+	resolving []dependency
+	// Объекты, внутри которых находится линкуемое значение, начиная с корня документа.
+	objects []objectFrame
+	// Путь к линкуемому значению - для сообщений об ошибках.
+	keyPath []string
 }
 
 func newScope(a ast2.WithPath) scope {
+	definitions := make(map[string]ast2.Definition, len(a.Root().Definitions()))
+	for _, def := range a.Root().Definitions() {
+		definitions[def.Name().String()] = def
+	}
+
 	return scope{
 		linkedByName: make(map[string]ast3.Ast),
 		ast:          a,
+		definitions:  definitions,
+		linkedDefs:   make(map[string]ast3.Expression),
+		linkedKVs:    make(map[types.Position]ast3.KV),
+		keyPath:      []string{rootVariable},
 	}
 }
 
@@ -43,52 +90,139 @@ type LinkParam struct {
 	MainAst ast2.WithPath
 	// AST по пути нахождения файла.
 	ASTByPath map[string]ast2.WithPath
+	// Уже готовые AST файлов других форматов (json, yaml, toml, .env) по пути.
+	Modules map[string]ast3.Ast
 	// Переменные среды.
 	Env map[string]string
+	// Файловая система для функции file().
+	FS FS
 }
 
 func (l *Linker) Link(param LinkParam) (ast3.Ast, error) {
 	l.astByPath = param.ASTByPath
 	l.env = param.Env
+	l.fs = param.FS
+	l.missingEnv = nil
+
+	for path, module := range param.Modules {
+		l.linkedByPath[path] = module
+	}
+
+	linked, err := l.link(newScope(param.MainAst))
 
-	return l.link(newScope(param.MainAst))
+	// Незаданные обязательные переменные важнее остальных ошибок:
+	// из-за подставленной вместо них пустой строки могли не сработать операторы и функции.
+	if len(l.missingEnv) > 0 {
+		return ast3.Ast{}, errors.Wrap(errors.Join(l.missingEnv...), "required environment variables are not set")
+	}
+
+	if err != nil {
+		return ast3.Ast{}, err
+	}
+
+	// Маркеры удаления и стратегии слияния нужны, пока импортированные файлы сливаются через spread,
+	// поэтому убираются только из итогового AST основного файла.
+	return ast3.NewAst(removeDeleted(linked.Root())), nil
 }
 
 func (l *Linker) link(scp scope) (ast3.Ast, error) {
 	for _, imp := range scp.ast.Imports() {
+		if paths, ok := scp.ast.ImportPaths(imp.Path().String()); ok {
+			linked, err := l.linkGlobImport(paths)
+			if err != nil {
+				return ast3.Ast{}, errors.Wrapf(err, "link ast, path: [%s]", imp.Path().String())
+			}
+
+			scp.linkedByName[imp.Name().String()] = linked
+
+			continue
+		}
+
 		absPath, ok := scp.ast.ImportPath(imp.Path().String())
 		if !ok {
 			return ast3.Ast{}, errors.New("get import absolute path by relative path")
 		}
 
-		if alreadyLinked, ok := l.linkedByPath[absPath]; ok {
-			scp.linkedByName[imp.Name().String()] = alreadyLinked
-			return alreadyLinked, nil
-		}
-
-		astForLink, ok := l.astByPath[absPath]
-		if !ok {
-			return ast3.Ast{}, errors.New("ast for link not found")
+		// Файла необязательного импорта нет - вместо него пустой объект, поэтому spread ничего не добавляет.
+		if imp.Optional() && !l.hasImport(absPath) {
+			scp.linkedByName[imp.Name().String()] = ast3.NewAst(ast3.NewObject([]ast3.KV{}))
+			continue
 		}
 
-		linked, err := l.link(newScope(astForLink))
+		linked, err := l.linkImport(absPath)
 		if err != nil {
 			return ast3.Ast{}, errors.Wrapf(err, "link ast, path: [%s]", imp.Path().String())
 		}
 
-		l.linkedByPath[absPath] = linked
 		scp.linkedByName[imp.Name().String()] = linked
 	}
 
-	obj, err := l.linkObject(scp, scp.ast.Root().Object())
+	root, err := l.linkExpression(scp, scp.ast.Root().Value())
+	if err != nil {
+		return ast3.Ast{}, errors.Wrap(err, "link root")
+	}
+
+	return ast3.NewAst(root), nil
+}
+
+// linkImport линкует импортированный файл. Каждый файл линкуется один раз.
+func (l *Linker) linkImport(absPath string) (ast3.Ast, error) {
+	if alreadyLinked, ok := l.linkedByPath[absPath]; ok {
+		return alreadyLinked, nil
+	}
+
+	astForLink, ok := l.astByPath[absPath]
+	if !ok {
+		return ast3.Ast{}, errors.New("ast for link not found")
+	}
+
+	linked, err := l.link(newScope(astForLink))
 	if err != nil {
-		return ast3.Ast{}, errors.Wrap(err, "link object")
+		return ast3.Ast{}, err
 	}
 
-	return ast3.NewAst(obj), nil
+	l.linkedByPath[absPath] = linked
+
+	return linked, nil
+}
+
+func (l *Linker) hasImport(absPath string) bool {
+	if _, ok := l.linkedByPath[absPath]; ok {
+		return true
+	}
+
+	_, ok := l.astByPath[absPath]
+
+	return ok
+}
+
+// linkGlobImport собирает файлы glob-импорта или импорта директории в объект: ключ - имя файла без расширения.
+func (l *Linker) linkGlobImport(paths []string) (ast3.Ast, error) {
+	kvs := make([]ast3.KV, 0, len(paths))
+	pathByName := make(map[string]string, len(paths))
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if other, ok := pathByName[name]; ok {
+			return ast3.Ast{}, errors.Wrapf(ErrDuplicateImportName, "%s: %s and %s", name, other, path)
+		}
+
+		pathByName[name] = path
+
+		linked, err := l.linkImport(path)
+		if err != nil {
+			return ast3.Ast{}, errors.Wrapf(err, "link file %s", path)
+		}
+
+		kvs = append(kvs, ast3.NewKV(ast3.NewIdent(name), linked.Root()))
+	}
+
+	return ast3.NewAst(ast3.NewObject(kvs)), nil
 }
 
 func (l *Linker) linkObject(scp scope, obj ast2.Object) (ast3.Object, error) {
+	scp.objects = append(append([]objectFrame{}, scp.objects...), objectFrame{object: obj, keyPath: scp.keyPath})
+
 	entries, err := l.linkEntries(scp, obj.Entries())
 	if err != nil {
 		return ast3.Object{}, errors.Wrap(err, "link entries")
@@ -97,6 +231,9 @@ func (l *Linker) linkObject(scp scope, obj ast2.Object) (ast3.Object, error) {
 	return ast3.NewObject(entries), nil
 }
 
+// linkEntries линкует записи объекта по порядку. Ключи и spread-ы равноправны:
+// каждая следующая запись сливается с уже собранными через mergeEntries, поэтому побеждает запись, которая стоит позже,
+// а вложенные объекты сливаются рекурсивно - и после ключа, и после другого spread-а.
 func (l *Linker) linkEntries(scp scope, entries []ast2.Entry) ([]ast3.KV, error) {
 	kvMap := orderedset.New[ast3.Ident, ast3.KV](0)
 
@@ -107,10 +244,9 @@ func (l *Linker) linkEntries(scp scope, entries []ast2.Entry) ([]ast3.KV, error)
 			if err != nil {
 				return nil, errors.Wrap(err, "link kv")
 			}
-			if existingEntry, exist := kvMap.Get(ent.Key()); exist {
-				kvMap.Set(ent.Key(), l.mergeEntries(existingEntry, ent))
-			} else {
-				kvMap.Set(ent.Key(), ent)
+
+			if err = l.setEntry(kvMap, ent); err != nil {
+				return nil, newErrMerge(err, e.Key().String(), e.Location(), scp.ast.Path())
 			}
 		case ast2.Spread:
 			spreadEntries, err := l.linkObjectSpread(scp, e)
@@ -119,7 +255,14 @@ func (l *Linker) linkEntries(scp scope, entries []ast2.Entry) ([]ast3.KV, error)
 			}
 
 			for _, spreadEntry := range spreadEntries {
-				kvMap.Set(spreadEntry.Key(), spreadEntry)
+				if err = l.setEntry(kvMap, spreadEntry); err != nil {
+					return nil, newErrMerge(
+						errors.Wrapf(err, "key %s", spreadEntry.Key().String()),
+						e.Var().String()+"...",
+						e.Location(),
+						scp.ast.Path(),
+					)
+				}
 			}
 		default:
 			return nil, errors.New("unknown entry type")
@@ -129,45 +272,160 @@ func (l *Linker) linkEntries(scp scope, entries []ast2.Entry) ([]ast3.KV, error)
 	return kvMap.Values(), nil
 }
 
+// setEntry добавляет запись в объект, сливая ее с уже существующей записью по тому же ключу.
+func (l *Linker) setEntry(kvMap *orderedset.OrderedSet[ast3.Ident, ast3.KV], ent ast3.KV) error {
+	existingEntry, exist := kvMap.Get(ent.Key())
+	if !exist {
+		kvMap.Set(ent.Key(), ent)
+		return nil
+	}
+
+	merged, err := l.mergeEntries(existingEntry, ent)
+	if err != nil {
+		return err
+	}
+
+	kvMap.Set(ent.Key(), merged)
+
+	return nil
+}
+
+// linkKV линкует значение ключа один раз: на ключ могут ссылаться через self и root раньше, чем до него дойдет очередь.
 func (l *Linker) linkKV(scp scope, kv ast2.KV) (ast3.KV, error) {
-	var value ast3.Expression
-	switch v := kv.Value().(type) {
+	pos := kv.Key().Location().Start()
+	if linked, ok := scp.linkedKVs[pos]; ok {
+		return linked, nil
+	}
+
+	scp.keyPath = append(append([]string{}, scp.keyPath...), kv.Key().String())
+
+	dep := dependency{
+		id:   fmt.Sprintf("key %d:%d", pos.Line(), pos.Column()),
+		name: strings.Join(scp.keyPath, "."),
+		loc:  kv.Location(),
+	}
+
+	scp, err := scp.resolve(dep)
+	if err != nil {
+		return ast3.KV{}, err
+	}
+
+	value, err := l.linkExpression(scp, kv.Value())
+	if err != nil {
+		return ast3.KV{}, err
+	}
+
+	linked := ast3.NewKV(ast3.NewIdent(kv.Key().String()), value).
+		SetDoc(kv.Doc().Text()).
+		SetMerge(mergeStrategy(kv.Merge()))
+	if kv.Merge() == ast2.MergeByKey {
+		linked = linked.SetMergeKey(kv.MergeKey().String())
+	}
+	scp.linkedKVs[pos] = linked
+
+	return linked, nil
+}
+
+func mergeStrategy(s ast2.MergeStrategy) ast3.MergeStrategy {
+	switch s {
+	case ast2.MergeAppend:
+		return ast3.MergeAppend
+	case ast2.MergePrepend:
+		return ast3.MergePrepend
+	case ast2.MergeReplace:
+		return ast3.MergeReplace
+	case ast2.MergeByKey:
+		return ast3.MergeByKey
+	default:
+		return ast3.MergeDefault
+	}
+}
+
+// linkExpression линкует значение ключа или элемент массива.
+func (l *Linker) linkExpression(scp scope, expr ast2.Expression) (ast3.Expression, error) {
+	switch v := expr.(type) {
 	case ast2.Object:
 		exp, err := l.linkObject(scp, v)
 		if err != nil {
-			return ast3.KV{}, errors.Wrap(err, "link object")
+			return nil, errors.Wrap(err, "link object")
 		}
 
-		value = exp
+		return exp, nil
 	case ast2.Array:
 		exp, err := l.linkArray(scp, v)
 		if err != nil {
-			return ast3.KV{}, errors.Wrap(err, "link array")
+			return nil, errors.Wrap(err, "link array")
 		}
 
-		value = exp
+		return exp, nil
 	case ast2.Var:
 		node, err := l.findVariableExp(scp, v)
 		if err != nil {
-			return ast3.KV{}, errors.Wrap(err, "find variable")
+			return nil, errors.Wrap(err, "find variable")
 		}
 
-		value = node
+		return node, nil
 	case ast2.Env:
-		value = ast3.NewString(l.getEnv(v.Name().String()))
+		exp, err := l.linkEnv(scp, v)
+		if err != nil {
+			return nil, errors.Wrap(err, "link env")
+		}
+
+		return exp, nil
+	case ast2.Template:
+		exp, err := l.linkTemplate(scp, v)
+		if err != nil {
+			return nil, errors.Wrap(err, "link template")
+		}
+
+		return exp, nil
+	case ast2.Binary:
+		exp, err := l.linkBinary(scp, v)
+		if err != nil {
+			return nil, errors.Wrap(err, "link binary expression")
+		}
+
+		return exp, nil
+	case ast2.Unary:
+		exp, err := l.linkUnary(scp, v)
+		if err != nil {
+			return nil, errors.Wrap(err, "link unary expression")
+		}
+
+		return exp, nil
+	case ast2.Conditional:
+		exp, err := l.linkConditional(scp, v)
+		if err != nil {
+			return nil, errors.Wrap(err, "link conditional expression")
+		}
+
+		return exp, nil
+	case ast2.Call:
+		exp, err := l.linkCall(scp, v)
+		if err != nil {
+			return nil, errors.Wrap(err, "link call")
+		}
+
+		return exp, nil
+	case ast2.Delete:
+		return ast3.NewDelete(), nil
+	case ast2.Null:
+		return ast3.NewNull(), nil
 	case ast2.Bool:
-		value = ast3.NewBool(v.Value())
+		return ast3.NewBool(v.Value()), nil
 	case ast2.String:
-		value = ast3.NewString(v.Value())
+		return ast3.NewString(v.Value()), nil
 	case ast2.Int:
-		value = ast3.NewInt(v.Value())
+		return ast3.NewInt(v.Value()), nil
 	case ast2.Float:
-		value = ast3.NewFloat(v.Value())
+		return ast3.NewFloat(v.Value()), nil
+	case ast2.Duration:
+		return ast3.NewDuration(v.Value()), nil
+	case ast2.ByteSize:
+		return ast3.NewByteSize(v.Value()), nil
 	default:
-		return ast3.KV{}, errors.New("unknown value type")
+		return nil, errors.New("unknown value type")
 	}
-
-	return ast3.NewKV(ast3.NewIdent(kv.Key().String()), value), nil
 }
 
 func (l *Linker) linkObjectSpread(scp scope, spread ast2.Spread) ([]ast3.KV, error) {
@@ -202,96 +460,369 @@ func (l *Linker) linkArray(scp scope, array ast2.Array) (ast3.Array, error) {
 	elems := make([]ast3.Expression, 0, len(array.Elements()))
 
 	for _, elem := range array.Elements() {
-		switch v := elem.(type) {
-		case ast2.Object:
-			exp, err := l.linkObject(scp, v)
-			if err != nil {
-				return ast3.Array{}, errors.Wrap(err, "link object")
-			}
-			elems = append(elems, exp)
-		case ast2.Spread:
-			exps, err := l.linkArraySpread(scp, v)
+		if spread, ok := elem.(ast2.Spread); ok {
+			exps, err := l.linkArraySpread(scp, spread)
 			if err != nil {
 				return ast3.Array{}, errors.Wrap(err, "link spread")
 			}
 
 			elems = append(elems, exps...)
-		case ast2.Array:
-			exp, err := l.linkArray(scp, v)
+
+			continue
+		}
+
+		exp, err := l.linkExpression(scp, elem)
+		if err != nil {
+			return ast3.Array{}, err
+		}
+
+		elems = append(elems, exp)
+	}
+
+	return ast3.NewArray(elems), nil
+}
+
+// linkTemplate собирает строку с интерполяцией. Подставлять можно только скалярные значения.
+func (l *Linker) linkTemplate(scp scope, template ast2.Template) (ast3.String, error) {
+	var b strings.Builder
+
+	for _, part := range template.Parts() {
+		switch v := part.(type) {
+		case ast2.String:
+			b.WriteString(v.Value())
+		case ast2.Env:
+			node, err := l.linkEnv(scp, v)
+			if err != nil {
+				return ast3.String{}, errors.Wrap(err, "link env")
+			}
+
+			str, err := interpolate(node)
 			if err != nil {
-				return ast3.Array{}, errors.Wrap(err, "link array")
+				return ast3.String{}, errors.Wrapf(
+					err,
+					"interpolate $%s at %d:%d",
+					v.Name().String(),
+					v.Location().Start().Line(),
+					v.Location().Start().Column(),
+				)
 			}
-			elems = append(elems, exp)
+
+			b.WriteString(str)
 		case ast2.Var:
 			node, err := l.findVariableExp(scp, v)
 			if err != nil {
-				return ast3.Array{}, errors.Wrap(err, "find variable")
+				return ast3.String{}, errors.Wrap(err, "find variable")
 			}
 
-			elems = append(elems, node)
-		case ast2.Env:
-			elems = append(elems, ast3.NewString(l.getEnv(v.Name().String())))
-		case ast2.Bool:
-			elems = append(elems, ast3.NewBool(v.Value()))
-		case ast2.String:
-			elems = append(elems, ast3.NewString(v.Value()))
-		case ast2.Int:
-			elems = append(elems, ast3.NewInt(v.Value()))
-		case ast2.Float:
-			elems = append(elems, ast3.NewFloat(v.Value()))
+			str, err := interpolate(node)
+			if err != nil {
+				return ast3.String{}, errors.Wrapf(
+					err,
+					"interpolate %s at %d:%d",
+					v.String(),
+					v.Location().Start().Line(),
+					v.Location().Start().Column(),
+				)
+			}
+
+			b.WriteString(str)
 		default:
-			return ast3.Array{}, errors.New("unknown value type")
+			return ast3.String{}, errors.New("unknown template part type")
 		}
 	}
 
-	return ast3.NewArray(elems), nil
+	return ast3.NewString(b.String()), nil
+}
+
+// interpolate возвращает строковое представление скалярного значения.
+func interpolate(exp ast3.Expression) (string, error) {
+	switch v := exp.(type) {
+	case ast3.String:
+		return v.Value(), nil
+	case ast3.Int:
+		return strconv.FormatInt(v.Value(), 10), nil
+	case ast3.Float:
+		return strconv.FormatFloat(v.Value(), 'f', -1, 64), nil
+	case ast3.Bool:
+		return strconv.FormatBool(v.Value()), nil
+	case ast3.Duration:
+		return v.Value().String(), nil
+	case ast3.ByteSize:
+		return strconv.FormatUint(v.Value(), 10), nil
+	default:
+		return "", errors.Wrap(ErrUnexpectedNodeType, "expected: scalar value")
+	}
 }
 
 func (l *Linker) findVariableExp(scp scope, v ast2.Var) (ast3.Expression, error) {
-	linkedAst, ok := scp.linkedByName[v.Path()[0].String()]
-	if !ok {
+	path := linkPath(v.Path()[1:])
+
+	var (
+		node ast3.Expression
+		err  error
+	)
+
+	if linkedAst, ok := scp.linkedByName[v.Path()[0].String()]; ok {
+		node, err = linkedAst.FindExpByPath(path)
+	} else if def, ok := scp.definitions[v.Path()[0].String()]; ok {
+		node, err = l.findDefinitionExp(scp, def, path)
+	} else if isSelfVariable(v.Path()[0].String()) {
+		node, err = l.findSelfExp(scp, v)
+	} else {
 		return nil, newErrNotFoundVariable(v.Path()[0].String())
 	}
 
-	node, err := linkedAst.FindExpByPath(lo.Map(v.Path()[1:], func(item ast2.Ident, _ int) ast3.Ident {
-		return ast3.NewIdent(item.String())
-	}))
 	switch {
 	case errors.Is(err, errors.ErrNotFound):
-		return nil, newErrNotFoundVariable(v.StringPath()...)
+		// Причина из поиска показывает, на какой части пути он остановился: например, индекс у не массива.
+		return nil, errors.Wrapf(newErrNotFoundVariable(v.String()), "find %s: %s", v.String(), err.Error())
+	case errors.Is(err, ast3.ErrIndexOutOfRange):
+		return nil, errors.Wrapf(err, "find %s", v.String())
 	case err != nil:
 		return nil, errors.Wrap(err, "find node by path")
 	}
 
+	// Удаленный ключ считается отсутствующим.
+	if _, ok := node.(ast3.Delete); ok {
+		return nil, newErrNotFoundVariable(v.String())
+	}
+
 	return node, nil
 }
 
+// linkPath переводит путь переменной без первой части в путь linker/ast.
+func linkPath(idents []ast2.Ident) []ast3.Ident {
+	return lo.Map(idents, func(item ast2.Ident, _ int) ast3.Ident {
+		if index, ok := item.(ast2.Index); ok {
+			return ast3.NewIndex(index.Index())
+		}
+
+		return ast3.NewIdent(item.String())
+	})
+}
+
+func (l *Linker) findDefinitionExp(scp scope, def ast2.Definition, path []ast3.Ident) (ast3.Expression, error) {
+	exp, err := l.linkDefinition(scp, def)
+	if err != nil {
+		return nil, err
+	}
+
+	return ast3.FindExpByPath(exp, path)
+}
+
+// linkDefinition линкует локальную переменную один раз и запоминает результат.
+func (l *Linker) linkDefinition(scp scope, def ast2.Definition) (ast3.Expression, error) {
+	name := def.Name().String()
+
+	if exp, ok := scp.linkedDefs[name]; ok {
+		return exp, nil
+	}
+
+	scp, err := scp.resolve(dependency{id: "definition " + name, name: name, loc: def.Location(), definition: true})
+	if err != nil {
+		return nil, err
+	}
+
+	// Локальная переменная объявлена вне объекта документа.
+	scp.objects = nil
+	scp.keyPath = []string{name}
+
+	exp, err := l.linkExpression(scp, def.Value())
+	if err != nil {
+		return nil, errors.Wrapf(err, "link definition %s", name)
+	}
+
+	scp.linkedDefs[name] = exp
+
+	return exp, nil
+}
+
 func (l *Linker) getEnv(name string) string {
 	return l.env[name]
 }
 
-func (l *Linker) mergeEntries(entry1, entry2 ast3.KV) ast3.KV {
+// linkEnv подставляет значение переменной среды, приведенное к ее типу.
+// Пустая переменная считается незаданной: вместо нее подставляется значение по умолчанию,
+// а для обязательной переменной запоминается ошибка - так при загрузке видны сразу все незаданные переменные.
+func (l *Linker) linkEnv(scp scope, env ast2.Env) (ast3.Expression, error) {
+	value := l.getEnv(env.Name().String())
+
+	if value == "" && env.Default() != nil {
+		exp, err := l.linkExpression(scp, env.Default())
+		if err != nil {
+			return nil, errors.Wrap(err, "link default")
+		}
+
+		return exp, nil
+	}
+
+	if value == "" && env.Required() {
+		l.missingEnv = append(l.missingEnv, newErrMissingEnv(env, scp.ast.Path()))
+
+		return ast3.NewString(""), nil
+	}
+
+	exp, err := convertEnv(value, env.Type())
+	if err != nil {
+		return nil, newErrInvalidEnv(err, env, scp.ast.Path())
+	}
+
+	return exp, nil
+}
+
+// mergeEntries сливает значение entry2 с существующим значением entry1 по стратегии entry2.
+// По умолчанию объекты сливаются рекурсивно, в остальных случаях значение entry2 заменяет значение entry1.
+// В том числе null заменяет объект целиком, а объект заменяет null.
+func (l *Linker) mergeEntries(entry1, entry2 ast3.KV) (ast3.KV, error) {
+	// Переопределение без документации сохраняет документацию исходного ключа.
+	doc := entry2.Doc()
+	if doc == "" {
+		doc = entry1.Doc()
+	}
+
+	// Стратегия применяется один раз: слитое значение дальше сливается как обычное.
+	merged := ast3.NewKV(entry1.Key(), entry2.Value()).SetDoc(doc)
+
+	// Удаленный ключ считается отсутствующим.
+	if _, ok := entry1.Value().(ast3.Delete); ok {
+		return entry2.SetDoc(doc), nil
+	}
+
+	switch entry2.Merge() {
+	case ast3.MergeReplace:
+		return merged, nil
+	case ast3.MergeByKey:
+		arr, err := l.mergeArraysByKey(entry1.Value(), entry2.Value(), entry2.MergeKey())
+		if err != nil {
+			return ast3.KV{}, err
+		}
+
+		return ast3.NewKV(entry1.Key(), arr).SetDoc(doc), nil
+	case ast3.MergeAppend, ast3.MergePrepend:
+		arr1, ok1 := entry1.Value().(ast3.Array)
+		arr2, ok2 := entry2.Value().(ast3.Array)
+		if !ok1 || !ok2 {
+			return ast3.KV{}, newErrInvalidMerge(entry2.Merge(), entry1.Value(), entry2.Value())
+		}
+
+		elems := make([]ast3.Expression, 0, len(arr1.Elements())+len(arr2.Elements()))
+		if entry2.Merge() == ast3.MergeAppend {
+			elems = append(append(elems, arr1.Elements()...), arr2.Elements()...)
+		} else {
+			elems = append(append(elems, arr2.Elements()...), arr1.Elements()...)
+		}
+
+		return ast3.NewKV(entry1.Key(), ast3.NewArray(elems)).SetDoc(doc), nil
+	}
+
 	v1, ok1 := entry1.Value().(ast3.Object)
 	v2, ok2 := entry2.Value().(ast3.Object)
 	if !ok1 || !ok2 {
-		return entry2
+		return merged, nil
 	}
 
 	kvMap := orderedset.New[ast3.Ident, ast3.KV](0)
-	for _, v := range v1.KV() {
-		if existingEntry, exist := kvMap.Get(v.Key()); exist {
-			kvMap.Set(v.Key(), l.mergeEntries(existingEntry, v))
-		} else {
-			kvMap.Set(v.Key(), v)
+	for _, v := range append(append([]ast3.KV{}, v1.KV()...), v2.KV()...) {
+		if err := l.setEntry(kvMap, v); err != nil {
+			return ast3.KV{}, errors.Wrapf(err, "key %s", v.Key().String())
 		}
 	}
-	for _, v := range v2.KV() {
-		if existingEntry, exist := kvMap.Get(v.Key()); exist {
-			kvMap.Set(v.Key(), l.mergeEntries(existingEntry, v))
-		} else {
-			kvMap.Set(v.Key(), v)
+
+	return ast3.NewKV(entry1.Key(), ast3.NewObject(kvMap.Values())).SetDoc(doc), nil
+}
+
+// mergeArraysByKey сливает массивы объектов по значению поля key.
+// Объекты с совпадающим значением сливаются рекурсивно на месте, остальные дописываются в конец.
+func (l *Linker) mergeArraysByKey(existing, value ast3.Expression, key string) (ast3.Array, error) {
+	arr1, ok1 := existing.(ast3.Array)
+	arr2, ok2 := value.(ast3.Array)
+	if !ok1 || !ok2 {
+		return ast3.Array{}, newErrInvalidMerge(ast3.MergeByKey, existing, value)
+	}
+
+	elems := append([]ast3.Expression{}, arr1.Elements()...)
+	indexByKey := make(map[string]int, len(elems))
+	for i, elem := range elems {
+		if k, ok := mergeKeyValue(elem, key); ok {
+			if _, exist := indexByKey[k]; !exist {
+				indexByKey[k] = i
+			}
 		}
 	}
 
-	return ast3.NewKV(entry1.Key(), ast3.NewObject(kvMap.Values()))
+	for i, elem := range arr2.Elements() {
+		k, ok := mergeKeyValue(elem, key)
+		if !ok {
+			return ast3.Array{}, errors.Wrapf(
+				ErrInvalidMerge,
+				"element %d: expected object with scalar %s, got %s",
+				i,
+				key,
+				typeName(elem),
+			)
+		}
+
+		j, exist := indexByKey[k]
+		if !exist {
+			indexByKey[k] = len(elems)
+			elems = append(elems, elem)
+
+			continue
+		}
+
+		merged, err := l.mergeEntries(ast3.NewKV(ast3.NewIdent(key), elems[j]), ast3.NewKV(ast3.NewIdent(key), elem))
+		if err != nil {
+			return ast3.Array{}, errors.Wrapf(err, "element %d", i)
+		}
+
+		elems[j] = merged.Value()
+	}
+
+	return ast3.NewArray(elems), nil
+}
+
+// mergeKeyValue возвращает значение поля key объекта вместе с типом, чтобы 1 и "1" различались.
+func mergeKeyValue(elem ast3.Expression, key string) (string, bool) {
+	obj, ok := elem.(ast3.Object)
+	if !ok {
+		return "", false
+	}
+
+	exp, err := obj.FindExpByPath([]ast3.Ident{ast3.NewIdent(key)})
+	if err != nil {
+		return "", false
+	}
+
+	str, err := interpolate(exp)
+	if err != nil {
+		return "", false
+	}
+
+	return typeName(exp) + ":" + str, true
+}
+
+// removeDeleted убирает из объектов ключи, помеченные на удаление, и сбрасывает стратегии слияния.
+func removeDeleted(exp ast3.Expression) ast3.Expression {
+	switch v := exp.(type) {
+	case ast3.Object:
+		kvs := make([]ast3.KV, 0, len(v.KV()))
+		for _, kv := range v.KV() {
+			if _, ok := kv.Value().(ast3.Delete); ok {
+				continue
+			}
+
+			kvs = append(kvs, ast3.NewKV(kv.Key(), removeDeleted(kv.Value())).SetDoc(kv.Doc()))
+		}
+
+		return ast3.NewObject(kvs)
+	case ast3.Array:
+		elems := make([]ast3.Expression, 0, len(v.Elements()))
+		for _, elem := range v.Elements() {
+			elems = append(elems, removeDeleted(elem))
+		}
+
+		return ast3.NewArray(elems)
+	default:
+		return exp
+	}
 }
@@ -0,0 +1,299 @@
+package linker
+
+import (
+	ast3 "github.com/atmxlab/atmc/linker/ast"
+	ast2 "github.com/atmxlab/atmc/parser/ast"
+	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/atmxlab/atmc/types"
+	"github.com/samber/lo"
+)
+
+const (
+	// rootVariable ссылается на корневой объект текущего документа. Корень документа должен быть объектом.
+	rootVariable = "root"
+	// selfVariable ссылается на ближайший объект, внутри которого находится значение.
+	selfVariable = "self"
+)
+
+func isSelfVariable(name string) bool {
+	return name == rootVariable || name == selfVariable
+}
+
+// dependency локальная переменная или ключ документа, который сейчас линкуется.
+type dependency struct {
+	id   string
+	name string
+	loc  types.Location
+	// Цикл, замкнутый на локальной переменной, считается циклом определений.
+	definition bool
+}
+
+// objectFrame объект документа и путь к нему.
+type objectFrame struct {
+	object  ast2.Object
+	keyPath []string
+}
+
+// resolve добавляет зависимость в цепочку линкуемых значений или возвращает ошибку, если она замыкает цикл.
+func (s scope) resolve(dep dependency) (scope, error) {
+	if i := lo.IndexOf(lo.Map(s.resolving, func(item dependency, _ int) string { return item.id }), dep.id); i >= 0 {
+		return s, newErrCycle(append(append([]dependency{}, s.resolving[i:]...), dep), s.ast.Path())
+	}
+
+	// Срез копируется, чтобы вложенные вызовы не портили его друг другу.
+	s.resolving = append(append([]dependency{}, s.resolving...), dep)
+
+	return s, nil
+}
+
+// findSelfExp находит значение по ссылке self или root.
+// По литералам объектов путь проходится без линковки, а линкуется только ключ, на котором путь заканчивается
+// или за которым нет литерала объекта, - так можно ссылаться на соседние ключи внутри еще не слинкованного объекта.
+func (l *Linker) findSelfExp(scp scope, v ast2.Var) (ast3.Expression, error) {
+	var chain []objectFrame
+
+	switch v.Path()[0].String() {
+	case rootVariable:
+		root, ok := scp.ast.Root().Value().(ast2.Object)
+		if !ok {
+			return nil, newErrRootNotObject(v, scp.ast.Path())
+		}
+
+		chain = []objectFrame{{object: root, keyPath: []string{rootVariable}}}
+	default:
+		if len(scp.objects) == 0 {
+			return nil, newErrSelfOutsideObject(v, scp.ast.Path())
+		}
+
+		chain = scp.objects
+	}
+
+	path := v.Path()[1:]
+	if len(path) == 0 {
+		return nil, newErrSelfReference(v, scp.ast.Path())
+	}
+
+	// Одинаковые ключи сливаются, поэтому значение ключа может быть собрано из нескольких объектов.
+	chains := [][]objectFrame{chain}
+
+	for i := 0; i < len(path); i++ {
+		key := path[i]
+
+		// Элемент массива-литерала тоже находится без линковки всего массива: root.replicas[0].port.
+		if index, ok := nextIndex(path, i); ok {
+			if chain, elem, ok := l.arrayElement(chains, key.String(), index); ok {
+				i++
+
+				frame := chain[len(chain)-1]
+				keyPath := append(append([]string{}, frame.keyPath...), key.String()+path[i].String())
+
+				if obj, ok := elem.(ast2.Object); ok && i < len(path)-1 {
+					chains = [][]objectFrame{append(append([]objectFrame{}, chain...), objectFrame{object: obj, keyPath: keyPath})}
+					continue
+				}
+
+				elemScp := scp
+				elemScp.objects = chain
+				elemScp.keyPath = append(append([]string{}, frame.keyPath...), key.String())
+
+				exp, err := l.linkExpression(elemScp, elem)
+				if err != nil {
+					return nil, err
+				}
+
+				return ast3.FindExpByPath(exp, linkPath(path[i+1:]))
+			}
+		}
+
+		nested, ok := l.nestedObjects(chains, key.String())
+		if ok && i < len(path)-1 {
+			chains = nested
+			continue
+		}
+
+		exp, err := l.linkKey(scp, chains, key.String())
+		if err != nil {
+			return nil, err
+		}
+
+		return ast3.FindExpByPath(exp, linkPath(path[i+1:]))
+	}
+
+	return nil, errors.NotFound("expression by path not found")
+}
+
+func nextIndex(path []ast2.Ident, i int) (int, bool) {
+	if i+1 >= len(path) {
+		return 0, false
+	}
+
+	index, ok := path[i+1].(ast2.Index)
+	if !ok {
+		return 0, false
+	}
+
+	return index.Index(), true
+}
+
+// arrayElement возвращает элемент массива-литерала, который лежит по ключу.
+// Если ключ объявлен несколько раз, в объектах есть spread или индекс вне массива, значение нужно линковать.
+func (l *Linker) arrayElement(chains [][]objectFrame, key string, index int) ([]objectFrame, ast2.Expression, bool) {
+	var (
+		found []objectFrame
+		arr   ast2.Array
+	)
+
+	for _, chain := range chains {
+		for _, entry := range chain[len(chain)-1].object.Entries() {
+			kv, ok := entry.(ast2.KV)
+			if !ok {
+				return nil, nil, false
+			}
+
+			if kv.Key().String() != key {
+				continue
+			}
+
+			value, ok := kv.Value().(ast2.Array)
+			if !ok || found != nil || kv.Merge() != ast2.MergeDefault {
+				return nil, nil, false
+			}
+
+			found, arr = chain, value
+		}
+	}
+
+	if found == nil || lo.ContainsBy(arr.Elements(), func(item ast2.Expression) bool {
+		_, ok := item.(ast2.Spread)
+		return ok
+	}) {
+		return nil, nil, false
+	}
+
+	if index < 0 {
+		index += len(arr.Elements())
+	}
+
+	if index < 0 || index >= len(arr.Elements()) {
+		return nil, nil, false
+	}
+
+	return found, arr.Elements()[index], true
+}
+
+// nestedObjects возвращает литералы объектов, которые лежат по ключу.
+// Если значение ключа не литерал объекта или в объектах есть spread, значение нужно линковать.
+func (l *Linker) nestedObjects(chains [][]objectFrame, key string) ([][]objectFrame, bool) {
+	nested := make([][]objectFrame, 0)
+
+	for _, chain := range chains {
+		frame := chain[len(chain)-1]
+
+		for _, entry := range frame.object.Entries() {
+			switch e := entry.(type) {
+			case ast2.KV:
+				if e.Key().String() != key {
+					continue
+				}
+
+				obj, ok := e.Value().(ast2.Object)
+				if !ok {
+					return nil, false
+				}
+
+				nested = append(nested, append(append([]objectFrame{}, chain...), objectFrame{
+					object:  obj,
+					keyPath: append(append([]string{}, frame.keyPath...), key),
+				}))
+			default:
+				return nil, false
+			}
+		}
+	}
+
+	return nested, len(nested) > 0
+}
+
+// linkKey линкует значение одного ключа так же, как linkEntries линкует объект целиком.
+func (l *Linker) linkKey(scp scope, chains [][]objectFrame, key string) (ast3.Expression, error) {
+	var (
+		merged ast3.KV
+		found  bool
+	)
+
+	for _, chain := range chains {
+		objScp := scp
+		objScp.objects = chain
+		objScp.keyPath = chain[len(chain)-1].keyPath
+
+		var (
+			current ast3.KV
+			exist   bool
+		)
+
+		for _, entry := range chain[len(chain)-1].object.Entries() {
+			switch e := entry.(type) {
+			case ast2.KV:
+				if e.Key().String() != key {
+					continue
+				}
+
+				ent, err := l.linkKV(objScp, e)
+				if err != nil {
+					return nil, errors.Wrap(err, "link kv")
+				}
+
+				if exist {
+					current, err = l.mergeEntries(current, ent)
+					if err != nil {
+						return nil, newErrMerge(err, key, e.Location(), scp.ast.Path())
+					}
+				} else {
+					current, exist = ent, true
+				}
+			case ast2.Spread:
+				spreadEntries, err := l.linkObjectSpread(objScp, e)
+				if err != nil {
+					return nil, errors.Wrap(err, "link spread")
+				}
+
+				ent, ok := lo.Find(spreadEntries, func(item ast3.KV) bool { return item.Key().String() == key })
+				if !ok {
+					continue
+				}
+
+				if exist {
+					current, err = l.mergeEntries(current, ent)
+					if err != nil {
+						return nil, newErrMerge(err, key, e.Location(), scp.ast.Path())
+					}
+				} else {
+					current, exist = ent, true
+				}
+			default:
+				return nil, errors.New("unknown entry type")
+			}
+		}
+
+		if !exist {
+			continue
+		}
+
+		if found {
+			var err error
+
+			merged, err = l.mergeEntries(merged, current)
+			if err != nil {
+				return nil, errors.Wrapf(err, "merge %s", key)
+			}
+		} else {
+			merged, found = current, true
+		}
+	}
+
+	if !found {
+		return nil, errors.NotFoundf("expression by path not found: %s", key)
+	}
+
+	return merged.Value(), nil
+}
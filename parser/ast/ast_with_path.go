@@ -4,6 +4,8 @@ type WithPath struct {
 	ast                    Ast
 	absPath                string
 	importAbsPathByRelPath map[string]string
+	// Файлы, подходящие под glob или лежащие в импортированной директории, в порядке сортировки.
+	importAbsPathsByRelPath map[string][]string
 }
 
 func NewWithPath(ast Ast, absPath string, importAbsPathByRelPath map[string]string) WithPath {
@@ -23,6 +25,17 @@ func (a WithPath) ImportPath(relPath string) (string, bool) {
 	return path, ok
 }
 
+func (a WithPath) SetImportPaths(importAbsPathsByRelPath map[string][]string) WithPath {
+	a.importAbsPathsByRelPath = importAbsPathsByRelPath
+	return a
+}
+
+// ImportPaths возвращает файлы glob-импорта или импорта директории.
+func (a WithPath) ImportPaths(relPath string) ([]string, bool) {
+	paths, ok := a.importAbsPathsByRelPath[relPath]
+	return paths, ok
+}
+
 func (a WithPath) Root() File {
 	return a.ast.Root()
 }
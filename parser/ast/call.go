@@ -0,0 +1,42 @@
+package ast
+
+import (
+	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/atmxlab/atmc/types"
+)
+
+// Call вызов функции: upper(name), file("./ca.pem").
+type Call struct {
+	expressionNode
+	name Ident
+	args []Expression
+}
+
+func (c Call) Name() Ident {
+	return c.name
+}
+
+func (c Call) Args() []Expression {
+	return c.args
+}
+
+func NewCall(name Ident, args []Expression, loc types.Location) Call {
+	c := Call{name: name, args: args}
+	c.loc = loc
+
+	return c
+}
+
+func (c Call) inspect(handler func(node Node) error) error {
+	if err := handler(c); err != nil {
+		return errors.Wrap(err, `failed to inspect call`)
+	}
+
+	for _, arg := range c.args {
+		if err := arg.inspect(handler); err != nil {
+			return errors.Wrap(err, `failed to inspect call argument`)
+		}
+	}
+
+	return nil
+}
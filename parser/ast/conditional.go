@@ -0,0 +1,53 @@
+package ast
+
+import (
+	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/atmxlab/atmc/types"
+)
+
+// Conditional условное выражение: if $STAGE == "prod" then 5 else 1.
+type Conditional struct {
+	expressionNode
+	cond      Expression
+	then      Expression
+	otherwise Expression
+}
+
+func (c Conditional) Condition() Expression {
+	return c.cond
+}
+
+func (c Conditional) Then() Expression {
+	return c.then
+}
+
+func (c Conditional) Else() Expression {
+	return c.otherwise
+}
+
+func NewConditional(cond, then, otherwise Expression, loc types.Location) Conditional {
+	c := Conditional{cond: cond, then: then, otherwise: otherwise}
+	c.loc = loc
+
+	return c
+}
+
+func (c Conditional) inspect(handler func(node Node) error) error {
+	if err := handler(c); err != nil {
+		return errors.Wrap(err, `failed to inspect conditional`)
+	}
+
+	if err := c.cond.inspect(handler); err != nil {
+		return errors.Wrap(err, `failed to inspect condition`)
+	}
+
+	if err := c.then.inspect(handler); err != nil {
+		return errors.Wrap(err, `failed to inspect then branch`)
+	}
+
+	if err := c.otherwise.inspect(handler); err != nil {
+		return errors.Wrap(err, `failed to inspect else branch`)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package ast
+
+import (
+	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/atmxlab/atmc/types"
+)
+
+// Definition локальная переменная файла: host = "db.internal".
+type Definition struct {
+	statementNode
+	name  Ident
+	value Expression
+}
+
+func NewDefinition(name Ident, value Expression) Definition {
+	d := Definition{name: name, value: value}
+	d.loc = types.NewLocation(
+		name.Location().Start(),
+		value.Location().End(),
+	)
+
+	return d
+}
+
+func (d Definition) Name() Ident {
+	return d.name
+}
+
+func (d Definition) Value() Expression {
+	return d.value
+}
+
+func (d Definition) inspect(handler func(node Node) error) error {
+	if err := handler(d); err != nil {
+		return errors.Wrap(err, "inspect definition node")
+	}
+
+	if err := d.value.inspect(handler); err != nil {
+		return errors.Wrap(err, "inspect definition value")
+	}
+
+	return nil
+}
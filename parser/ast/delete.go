@@ -0,0 +1,27 @@
+package ast
+
+import (
+	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/atmxlab/atmc/types"
+)
+
+// Delete маркер удаления ключа: tracing: delete.
+// Допустим только как значение ключа. Ключ удаляется из объекта, с которым сливается.
+type Delete struct {
+	expressionNode
+}
+
+func NewDelete(loc types.Location) Delete {
+	d := Delete{}
+	d.loc = loc
+
+	return d
+}
+
+func (d Delete) inspect(handler func(node Node) error) error {
+	if err := handler(d); err != nil {
+		return errors.Wrap(err, `failed to inspect delete`)
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package ast
+
+import (
+	"github.com/atmxlab/atmc/types"
+)
+
+// Doc документирующий комментарий (///).
+// Несколько подряд идущих строк объединяются в один Doc.
+type Doc struct {
+	node
+	text string
+}
+
+func NewDoc(text string, loc types.Location) Doc {
+	d := Doc{text: text}
+	d.loc = loc
+
+	return d
+}
+
+func (d Doc) Text() string {
+	return d.text
+}
+
+func (d Doc) IsEmpty() bool {
+	return d.text == ""
+}
@@ -9,6 +9,10 @@ type KV struct {
 	entryNode
 	key   Ident
 	value Expression
+	doc   Doc
+	merge MergeStrategy
+	// Поле, по которому сливаются массивы объектов при MergeByKey.
+	mergeKey Ident
 }
 
 func (kv KV) Key() Ident {
@@ -19,6 +23,37 @@ func (kv KV) Value() Expression {
 	return kv.value
 }
 
+// Doc документирующий комментарий, который идет перед ключом.
+func (kv KV) Doc() Doc {
+	return kv.doc
+}
+
+func (kv KV) SetDoc(doc Doc) KV {
+	kv.doc = doc
+	return kv
+}
+
+// Merge способ слияния значения с уже существующим значением ключа.
+func (kv KV) Merge() MergeStrategy {
+	return kv.merge
+}
+
+func (kv KV) SetMerge(merge MergeStrategy) KV {
+	kv.merge = merge
+	return kv
+}
+
+// MergeKey поле, по которому сливаются массивы объектов: services @merge(name): [...].
+func (kv KV) MergeKey() Ident {
+	return kv.mergeKey
+}
+
+func (kv KV) SetMergeKey(key Ident) KV {
+	kv.merge = MergeByKey
+	kv.mergeKey = key
+	return kv
+}
+
 func NewKV(key Ident, value Expression) KV {
 	e := KV{key: key, value: value}
 	e.loc = types.NewLocation(
@@ -5,15 +5,79 @@ import (
 	"github.com/atmxlab/atmc/types"
 )
 
+// EnvType - тип, к которому приводится значение переменной среды: $PORT:int.
+type EnvType string
+
+const (
+	EnvTypeString   EnvType = "string"
+	EnvTypeInt      EnvType = "int"
+	EnvTypeFloat    EnvType = "float"
+	EnvTypeBool     EnvType = "bool"
+	EnvTypeDuration EnvType = "duration"
+	EnvTypeJSON     EnvType = "json"
+)
+
+func NewEnvType(typ string, loc types.Location) (EnvType, error) {
+	switch t := EnvType(typ); t {
+	case EnvTypeString, EnvTypeInt, EnvTypeFloat, EnvTypeBool, EnvTypeDuration, EnvTypeJSON:
+		return t, nil
+	default:
+		return "", errors.Wrapf(
+			ErrUnknownEnvType,
+			"%s at %d:%d",
+			typ,
+			loc.Start().Line(),
+			loc.Start().Column(),
+		)
+	}
+}
+
 type Env struct {
 	expressionNode
 	name Ident
+	// Тип значения. Без явного типа значение - строка.
+	typ EnvType
+	// Значение по умолчанию: $PORT ?? 8080. nil, если не задано.
+	def Expression
+	// Обязательная переменная: $DB_PASSWORD!.
+	required bool
 }
 
 func (e Env) Name() Ident {
 	return e.name
 }
 
+func (e Env) Type() EnvType {
+	if e.typ == "" {
+		return EnvTypeString
+	}
+
+	return e.typ
+}
+
+func (e Env) SetType(typ EnvType) Env {
+	e.typ = typ
+	return e
+}
+
+func (e Env) Default() Expression {
+	return e.def
+}
+
+func (e Env) Required() bool {
+	return e.required
+}
+
+func (e Env) SetDefault(def Expression) Env {
+	e.def = def
+	return e
+}
+
+func (e Env) SetRequired(required bool) Env {
+	e.required = required
+	return e
+}
+
 func NewEnv(name Ident, loc types.Location) Env {
 	e := Env{name: name}
 	e.loc = loc
@@ -26,5 +90,11 @@ func (e Env) inspect(handler func(node Node) error) error {
 		return errors.Wrap(err, `failed to inspect env`)
 	}
 
+	if e.def != nil {
+		if err := e.def.inspect(handler); err != nil {
+			return errors.Wrap(err, `failed to inspect env default`)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,11 @@
+package ast
+
+import (
+	"github.com/atmxlab/atmc/pkg/errors"
+)
+
+var (
+	ErrNumberOverflow = errors.New("number overflow")
+	ErrInvalidNumber  = errors.New("invalid number")
+	ErrUnknownEnvType = errors.New("unknown env type")
+)
@@ -7,28 +7,45 @@ import (
 
 type File struct {
 	node
-	imports []Import
-	object  Object
+	imports     []Import
+	definitions []Definition
+	value       Expression
 }
 
 func (f File) Imports() []Import {
 	return f.imports
 }
 
-func (f File) Object() Object {
-	return f.object
+// Definitions локальные переменные файла.
+func (f File) Definitions() []Definition {
+	return f.definitions
 }
 
-func NewFile(imports []Import, object Object) File {
-	f := File{imports: imports, object: object}
+func (f File) SetDefinitions(definitions []Definition) File {
+	f.definitions = definitions
 
-	start := object.Location().Start()
+	if len(definitions) > 0 && (len(f.imports) == 0 || definitions[0].Location().Start().Line() < f.loc.Start().Line()) {
+		f.loc = types.NewLocation(definitions[0].Location().Start(), f.loc.End())
+	}
+
+	return f
+}
+
+// Value корневое значение документа: объект, массив или любое другое выражение.
+func (f File) Value() Expression {
+	return f.value
+}
+
+func NewFile(imports []Import, value Expression) File {
+	f := File{imports: imports, value: value}
+
+	start := value.Location().Start()
 
 	if len(imports) > 0 {
 		start = imports[0].Location().Start()
 	}
 
-	end := object.Location().End()
+	end := value.Location().End()
 
 	f.loc = types.NewLocation(start, end)
 
@@ -46,8 +63,14 @@ func (f File) inspect(handler func(node Node) error) error {
 		}
 	}
 
-	if err := f.object.inspect(handler); err != nil {
-		return errors.Wrap(err, "inspecting object node")
+	for _, def := range f.definitions {
+		if err := def.inspect(handler); err != nil {
+			return errors.Wrap(err, "inspecting definition node")
+		}
+	}
+
+	if err := f.value.inspect(handler); err != nil {
+		return errors.Wrap(err, "inspecting value node")
 	}
 
 	return nil
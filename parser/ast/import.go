@@ -1,6 +1,8 @@
 package ast
 
 import (
+	"strings"
+
 	"github.com/atmxlab/atmc/pkg/errors"
 	"github.com/atmxlab/atmc/types"
 )
@@ -9,6 +11,8 @@ type Import struct {
 	statementNode
 	name Ident
 	path Path
+	// Необязательный импорт: если файла нет, вместо него используется пустой объект.
+	optional bool
 }
 
 type Path struct {
@@ -22,6 +26,16 @@ func NewPath(string string, loc types.Location) Path {
 	return p
 }
 
+// IsGlob сообщает, что путь - шаблон нескольких файлов: ./services/*.atmc.
+func (p Path) IsGlob() bool {
+	return strings.Contains(p.String(), "*")
+}
+
+// IsDir сообщает, что путь - директория: ./services/.
+func (p Path) IsDir() bool {
+	return strings.HasSuffix(p.String(), "/")
+}
+
 func NewImport(
 	name Ident,
 	path Path,
@@ -35,6 +49,15 @@ func NewImport(
 	return i
 }
 
+func (i Import) Optional() bool {
+	return i.optional
+}
+
+func (i Import) SetOptional(optional bool) Import {
+	i.optional = optional
+	return i
+}
+
 func (i Import) Path() Path {
 	return i.path
 }
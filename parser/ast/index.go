@@ -0,0 +1,34 @@
+package ast
+
+import (
+	"strconv"
+
+	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/atmxlab/atmc/types"
+)
+
+// Index сегмент пути переменной - индекс элемента массива: hosts[0], clusters[-1].
+// Отрицательный индекс считается с конца массива.
+type Index struct {
+	identNode
+	index int
+}
+
+func NewIndex(index int, loc types.Location) Index {
+	i := Index{identNode: identNode{string: "[" + strconv.Itoa(index) + "]"}, index: index}
+	i.loc = loc
+
+	return i
+}
+
+func (i Index) Index() int {
+	return i.index
+}
+
+func (i Index) inspect(handler func(Node) error) error {
+	if err := handler(i); err != nil {
+		return errors.Wrap(err, "failed to inspect index")
+	}
+
+	return nil
+}
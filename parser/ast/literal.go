@@ -1,7 +1,11 @@
 package ast
 
 import (
+	"math"
+	"math/big"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/atmxlab/atmc/pkg/errors"
 	"github.com/atmxlab/atmc/types"
@@ -9,10 +13,17 @@ import (
 
 type Int = literalNode[int64]
 
+// NewInt парсит целое число: десятичное, 0x (hex), 0o (octal), 0b (binary), с разделителями "_".
 func NewInt(int string, loc types.Location) (Int, error) {
-	value, err := strconv.ParseInt(int, 10, 64)
+	value, err := parseInt(int)
 	if err != nil {
-		return Int{}, errors.Wrap(err, "error parsing integer")
+		return Int{}, errors.Wrapf(
+			err,
+			"error parsing integer %s at %d:%d",
+			int,
+			loc.Start().Line(),
+			loc.Start().Column(),
+		)
 	}
 
 	i := Int{value: value}
@@ -21,12 +32,85 @@ func NewInt(int string, loc types.Location) (Int, error) {
 	return i, nil
 }
 
+func parseInt(str string) (int64, error) {
+	s := strings.ReplaceAll(str, "_", "")
+
+	negative := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		negative = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	base := 10
+	if len(s) > 2 && s[0] == '0' {
+		switch s[1] {
+		case 'x', 'X':
+			base = 16
+		case 'o', 'O':
+			base = 8
+		case 'b', 'B':
+			base = 2
+		}
+
+		if base != 10 {
+			s = s[2:]
+		}
+	}
+
+	// Ведущий ноль запрещен: 0755 легко принять за восьмеричное число, например права на файл.
+	if base == 10 && len(s) > 1 && s[0] == '0' {
+		return 0, errors.Wrapf(ErrInvalidNumber, "leading zero in decimal %s, use 0o prefix for octal", str)
+	}
+
+	u, err := strconv.ParseUint(s, base, 64)
+	switch {
+	case errors.Is(err, strconv.ErrRange):
+		return 0, errors.Wrap(ErrNumberOverflow, "value out of int64 range")
+	case err != nil:
+		return 0, errors.Wrap(ErrInvalidNumber, err.Error())
+	}
+
+	if negative {
+		if u > -math.MinInt64 {
+			return 0, errors.Wrap(ErrNumberOverflow, "value out of int64 range")
+		}
+
+		return -int64(u), nil
+	}
+
+	if u > math.MaxInt64 {
+		return 0, errors.Wrap(ErrNumberOverflow, "value out of int64 range")
+	}
+
+	return int64(u), nil
+}
+
 type Float = literalNode[float64]
 
+// NewFloat парсит число с плавающей точкой: 1.5, .5, 1e-9, 1_000.5.
 func NewFloat(float64 string, loc types.Location) (Float, error) {
-	value, err := strconv.ParseFloat(float64, 10)
-	if err != nil {
-		return Float{}, errors.Wrap(err, "error parsing float")
+	value, err := strconv.ParseFloat(strings.ReplaceAll(float64, "_", ""), 64)
+	switch {
+	case errors.Is(err, strconv.ErrRange):
+		return Float{}, errors.Wrapf(
+			ErrNumberOverflow,
+			"float %s out of float64 range at %d:%d",
+			float64,
+			loc.Start().Line(),
+			loc.Start().Column(),
+		)
+	case err != nil:
+		return Float{}, errors.Wrapf(
+			ErrInvalidNumber,
+			"error parsing float %s at %d:%d: %s",
+			float64,
+			loc.Start().Line(),
+			loc.Start().Column(),
+			err.Error(),
+		)
 	}
 
 	f := Float{value: value}
@@ -35,6 +119,98 @@ func NewFloat(float64 string, loc types.Location) (Float, error) {
 	return f, nil
 }
 
+type Duration = literalNode[time.Duration]
+
+// NewDuration парсит длительность в формате time.ParseDuration: 250ms, 1m30s, 1.5h, с разделителями "_".
+func NewDuration(duration string, loc types.Location) (Duration, error) {
+	value, err := time.ParseDuration(strings.ReplaceAll(duration, "_", ""))
+	if err != nil {
+		return Duration{}, errors.Wrapf(
+			ErrInvalidNumber,
+			"error parsing duration %s at %d:%d: %s",
+			duration,
+			loc.Start().Line(),
+			loc.Start().Column(),
+			err.Error(),
+		)
+	}
+
+	d := Duration{value: value}
+	d.loc = loc
+
+	return d, nil
+}
+
+// byteUnits - множители единиц размера: SI - степени 1000, IEC - степени 1024.
+var byteUnits = map[string]uint64{
+	"B":   1,
+	"kB":  1e3,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"PB":  1e15,
+	"EB":  1e18,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+	"TiB": 1 << 40,
+	"PiB": 1 << 50,
+	"EiB": 1 << 60,
+}
+
+type ByteSize = literalNode[uint64]
+
+// NewByteSize парсит размер в байтах: 512MiB, 10GB, 1.5KiB, 1_500MB.
+// Дробная часть допустима, только если в итоге получается целое число байт.
+func NewByteSize(size string, loc types.Location) (ByteSize, error) {
+	value, err := parseByteSize(strings.ReplaceAll(size, "_", ""))
+	if err != nil {
+		return ByteSize{}, errors.Wrapf(
+			err,
+			"error parsing byte size %s at %d:%d",
+			size,
+			loc.Start().Line(),
+			loc.Start().Column(),
+		)
+	}
+
+	b := ByteSize{value: value}
+	b.loc = loc
+
+	return b, nil
+}
+
+func parseByteSize(str string) (uint64, error) {
+	i := strings.IndexFunc(str, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i <= 0 {
+		return 0, errors.Wrap(ErrInvalidNumber, "missing number or unit")
+	}
+
+	unit, ok := byteUnits[str[i:]]
+	if !ok {
+		return 0, errors.Wrapf(ErrInvalidNumber, "unknown unit %s", str[i:])
+	}
+
+	number, ok := new(big.Rat).SetString(str[:i])
+	if !ok {
+		return 0, errors.Wrapf(ErrInvalidNumber, "invalid number %s", str[:i])
+	}
+
+	number.Mul(number, new(big.Rat).SetUint64(unit))
+	if !number.IsInt() {
+		return 0, errors.Wrap(ErrInvalidNumber, "value is not a whole number of bytes")
+	}
+
+	if !number.Num().IsUint64() {
+		return 0, errors.Wrap(ErrNumberOverflow, "value out of uint64 range")
+	}
+
+	return number.Num().Uint64(), nil
+}
+
 type String = literalNode[string]
 
 func NewString(string string, loc types.Location) String {
@@ -0,0 +1,158 @@
+package ast_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/atmxlab/atmc/parser/ast"
+	"github.com/atmxlab/atmc/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInt(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected int64
+		err      error
+	}{
+		{name: "decimal", input: "123", expected: 123},
+		{name: "negative decimal", input: "-123", expected: -123},
+		{name: "zero", input: "0", expected: 0},
+		{name: "decimal with leading zero", input: "0755", err: ast.ErrInvalidNumber},
+		{name: "negative decimal with leading zero", input: "-0_755", err: ast.ErrInvalidNumber},
+		{name: "underscores", input: "1_000_000", expected: 1000000},
+		{name: "hex", input: "0x1F", expected: 31},
+		{name: "negative hex", input: "-0xff", expected: -255},
+		{name: "octal", input: "0o755", expected: 493},
+		{name: "binary", input: "0b1010", expected: 10},
+		{name: "binary with underscores", input: "0b_1111_0000", expected: 240},
+		{name: "max int64", input: "9223372036854775807", expected: math.MaxInt64},
+		{name: "min int64", input: "-0x8000000000000000", expected: math.MinInt64},
+		{name: "overflow", input: "9223372036854775808", err: ast.ErrNumberOverflow},
+		{name: "negative overflow", input: "-9223372036854775809", err: ast.ErrNumberOverflow},
+		{name: "hex overflow", input: "0x1_0000_0000_0000_0000", err: ast.ErrNumberOverflow},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			i, err := ast.NewInt(tc.input, types.Location{})
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, i.Value())
+		})
+	}
+}
+
+func TestNewFloat(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected float64
+		err      error
+	}{
+		{name: "simple", input: "123.321", expected: 123.321},
+		{name: "without integer part", input: ".5", expected: 0.5},
+		{name: "negative without integer part", input: "-.5", expected: -0.5},
+		{name: "exponent", input: "1e-9", expected: 1e-9},
+		{name: "exponent with fraction", input: "2.5E+3", expected: 2500},
+		{name: "underscores", input: "1_000.000_1", expected: 1000.0001},
+		{name: "overflow", input: "1e400", err: ast.ErrNumberOverflow},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := ast.NewFloat(tc.input, types.Location{})
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, f.Value())
+		})
+	}
+}
+
+func TestNewDuration(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected time.Duration
+		err      error
+	}{
+		{name: "milliseconds", input: "250ms", expected: 250 * time.Millisecond},
+		{name: "compound", input: "1m30s", expected: 90 * time.Second},
+		{name: "fractional", input: "1.5h", expected: 90 * time.Minute},
+		{name: "negative", input: "-10s", expected: -10 * time.Second},
+		{name: "micro sign", input: "10µs", expected: 10 * time.Microsecond},
+		{name: "underscores", input: "1_000ms", expected: time.Second},
+		{name: "overflow", input: "3000000h", err: ast.ErrInvalidNumber},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := ast.NewDuration(tc.input, types.Location{})
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, d.Value())
+		})
+	}
+}
+
+func TestNewByteSize(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected uint64
+		err      error
+	}{
+		{name: "bytes", input: "100B", expected: 100},
+		{name: "si kilobytes", input: "64kB", expected: 64_000},
+		{name: "si gigabytes", input: "10GB", expected: 10_000_000_000},
+		{name: "iec mebibytes", input: "512MiB", expected: 512 << 20},
+		{name: "fractional", input: "1.5KiB", expected: 1536},
+		{name: "underscores", input: "1_500MB", expected: 1_500_000_000},
+		{name: "max", input: "15EiB", expected: 15 << 60},
+		{name: "fractional bytes", input: "1.5B", err: ast.ErrInvalidNumber},
+		{name: "overflow", input: "16EiB", err: ast.ErrNumberOverflow},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := ast.NewByteSize(tc.input, types.Location{})
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, b.Value())
+		})
+	}
+}
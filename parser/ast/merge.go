@@ -0,0 +1,33 @@
+package ast
+
+// MergeStrategy способ слияния значения ключа со значением, которое у ключа уже есть.
+type MergeStrategy uint8
+
+const (
+	// MergeDefault объекты сливаются рекурсивно, остальные значения заменяются: key: value.
+	MergeDefault MergeStrategy = iota
+	// MergeAppend массив дописывается в конец существующего: level +: ["debug"].
+	MergeAppend
+	// MergePrepend массив дописывается в начало существующего: brokers ^: ["kafka-0:9092"].
+	MergePrepend
+	// MergeReplace значение заменяется целиком, в том числе объект: logging =: {level: "warn"}.
+	MergeReplace
+	// MergeByKey массивы объектов сливаются по значению поля: services @merge(name): [...].
+	// Объекты с совпадающим значением поля сливаются рекурсивно, остальные дописываются в конец.
+	MergeByKey
+)
+
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeAppend:
+		return "+:"
+	case MergePrepend:
+		return "^:"
+	case MergeReplace:
+		return "=:"
+	case MergeByKey:
+		return "@merge"
+	default:
+		return ":"
+	}
+}
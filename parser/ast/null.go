@@ -0,0 +1,28 @@
+package ast
+
+import (
+	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/atmxlab/atmc/types"
+)
+
+// Null литерал null - явное отсутствие значения.
+type Null struct {
+	expressionNode
+}
+
+func (Null) isLiteral() {}
+
+func NewNull(loc types.Location) Null {
+	n := Null{}
+	n.loc = loc
+
+	return n
+}
+
+func (n Null) inspect(handler func(node Node) error) error {
+	if err := handler(n); err != nil {
+		return errors.Wrap(err, `failed to inspect null`)
+	}
+
+	return nil
+}
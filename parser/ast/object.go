@@ -24,6 +24,10 @@ func NewObject(entries []Entry, loc types.Location) Object {
 }
 
 func (o Object) inspect(handler func(node Node) error) error {
+	if err := handler(o); err != nil {
+		return errors.Wrap(err, "inspect object")
+	}
+
 	for _, entry := range o.entries {
 		if err := entry.inspect(handler); err != nil {
 			return errors.Wrap(err, "inspect entry")
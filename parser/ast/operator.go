@@ -0,0 +1,108 @@
+package ast
+
+import (
+	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/atmxlab/atmc/types"
+)
+
+type Operator string
+
+const (
+	OpAdd       Operator = "+"
+	OpSub       Operator = "-"
+	OpMul       Operator = "*"
+	OpDiv       Operator = "/"
+	OpMod       Operator = "%"
+	OpEq        Operator = "=="
+	OpNotEq     Operator = "!="
+	OpLess      Operator = "<"
+	OpLessEq    Operator = "<="
+	OpGreater   Operator = ">"
+	OpGreaterEq Operator = ">="
+	OpAnd       Operator = "&&"
+	OpOr        Operator = "||"
+	OpNot       Operator = "!"
+)
+
+// Binary бинарное выражение: base.max_conns * 2, prefix + "-worker".
+type Binary struct {
+	expressionNode
+	op Operator
+	// Позиция оператора - для ошибок вычисления.
+	opPos types.Position
+	left  Expression
+	right Expression
+}
+
+func (b Binary) Operator() Operator {
+	return b.op
+}
+
+func (b Binary) OperatorPosition() types.Position {
+	return b.opPos
+}
+
+func (b Binary) Left() Expression {
+	return b.left
+}
+
+func (b Binary) Right() Expression {
+	return b.right
+}
+
+func NewBinary(op Operator, opPos types.Position, left, right Expression) Binary {
+	b := Binary{op: op, opPos: opPos, left: left, right: right}
+	b.loc = types.NewLocation(left.Location().Start(), right.Location().End())
+
+	return b
+}
+
+func (b Binary) inspect(handler func(node Node) error) error {
+	if err := handler(b); err != nil {
+		return errors.Wrap(err, `failed to inspect binary`)
+	}
+
+	if err := b.left.inspect(handler); err != nil {
+		return errors.Wrap(err, `failed to inspect left operand`)
+	}
+
+	if err := b.right.inspect(handler); err != nil {
+		return errors.Wrap(err, `failed to inspect right operand`)
+	}
+
+	return nil
+}
+
+// Unary унарное выражение: -x, !flag.
+type Unary struct {
+	expressionNode
+	op      Operator
+	operand Expression
+}
+
+func (u Unary) Operator() Operator {
+	return u.op
+}
+
+func (u Unary) Operand() Expression {
+	return u.operand
+}
+
+func NewUnary(op Operator, operand Expression, loc types.Location) Unary {
+	u := Unary{op: op, operand: operand}
+	u.loc = loc
+
+	return u
+}
+
+func (u Unary) inspect(handler func(node Node) error) error {
+	if err := handler(u); err != nil {
+		return errors.Wrap(err, `failed to inspect unary`)
+	}
+
+	if err := u.operand.inspect(handler); err != nil {
+		return errors.Wrap(err, `failed to inspect operand`)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package ast
+
+import (
+	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/atmxlab/atmc/types"
+)
+
+// Template строка с интерполяцией: "postgres://${db.user}@${$PG_HOST}".
+// Части - литералы String и подставляемые выражения Var и Env.
+type Template struct {
+	expressionNode
+	parts []Expression
+}
+
+func (t Template) Parts() []Expression {
+	return t.parts
+}
+
+func NewTemplate(parts []Expression, loc types.Location) Template {
+	t := Template{parts: parts}
+	t.loc = loc
+
+	return t
+}
+
+func (t Template) inspect(handler func(node Node) error) error {
+	if err := handler(t); err != nil {
+		return errors.Wrap(err, `failed to inspect template`)
+	}
+
+	for _, part := range t.parts {
+		if err := part.inspect(handler); err != nil {
+			return errors.Wrap(err, `failed to inspect template part`)
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package ast
 
 import (
+	"strings"
+
 	"github.com/atmxlab/atmc/pkg/errors"
 	"github.com/atmxlab/atmc/types"
 	"github.com/samber/lo"
@@ -21,6 +23,21 @@ func (v Var) StringPath() []string {
 	})
 }
 
+// String путь переменной в том виде, как он записан: clusters[1].endpoint.
+func (v Var) String() string {
+	var b strings.Builder
+
+	for i, item := range v.path {
+		if _, ok := item.(Index); !ok && i > 0 {
+			b.WriteString(".")
+		}
+
+		b.WriteString(item.String())
+	}
+
+	return b.String()
+}
+
 func NewVar(path []Ident) Var {
 	v := Var{path: path}
 
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"fmt"
+
+	token2 "github.com/atmxlab/atmc/types/token"
+)
+
+// docMover перемещается по токенам без документирующих комментариев.
+// Комментарии запоминаются у следующего за ними токена: привязать их можно только к ключу объекта,
+// в остальных местах они пропускаются как обычные комментарии.
+type docMover struct {
+	tokens          []token2.Token
+	docs            map[int][]token2.Token
+	pos             int
+	savePointsStack []int
+}
+
+func newDocMover(mover TokenMover) *docMover {
+	m := &docMover{docs: make(map[int][]token2.Token)}
+
+	var docs []token2.Token
+
+	for ; !mover.IsEmpty(); mover.Next() {
+		tok := mover.Token()
+		if tok.Type() == token2.DocComment {
+			docs = append(docs, tok)
+			continue
+		}
+
+		if len(docs) > 0 {
+			m.docs[len(m.tokens)] = docs
+			docs = nil
+		}
+
+		m.tokens = append(m.tokens, tok)
+	}
+
+	return m
+}
+
+// Docs возвращает документирующие комментарии перед текущим токеном.
+func (m *docMover) Docs() []token2.Token {
+	return m.docs[m.pos]
+}
+
+func (m *docMover) SavePoint() {
+	m.savePointsStack = append(m.savePointsStack, m.pos)
+}
+
+func (m *docMover) RemoveSavePoint() {
+	if len(m.savePointsStack) == 0 {
+		return
+	}
+
+	m.savePointsStack = m.savePointsStack[:len(m.savePointsStack)-1]
+}
+
+func (m *docMover) ReturnToSavePoint() {
+	if len(m.savePointsStack) == 0 {
+		return
+	}
+
+	m.pos = m.savePointsStack[len(m.savePointsStack)-1]
+}
+
+func (m *docMover) IsEmpty() bool {
+	return m.pos >= len(m.tokens)
+}
+
+func (m *docMover) Next() {
+	m.pos++
+}
+
+func (m *docMover) Prev() {
+	m.pos--
+}
+
+func (m *docMover) Token() token2.Token {
+	if m.pos >= len(m.tokens) {
+		panic(fmt.Sprintf("token pos out of range [pos: %d; len: %d]", m.pos, len(m.tokens)))
+	}
+
+	return m.tokens[m.pos]
+}
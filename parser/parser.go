@@ -1,12 +1,17 @@
 package parser
 
 import (
+	"path/filepath"
+	"strings"
+
 	ast2 "github.com/atmxlab/atmc/parser/ast"
 	"github.com/atmxlab/atmc/pkg/errors"
 	"github.com/atmxlab/atmc/types"
 	token2 "github.com/atmxlab/atmc/types/token"
 )
 
+const optionalImportPrefix = "?"
+
 type TokenMover interface {
 	Token() token2.Token
 	Next()
@@ -18,7 +23,7 @@ type TokenMover interface {
 }
 
 type Parser struct {
-	mover TokenMover
+	mover *docMover
 }
 
 func New() *Parser {
@@ -26,7 +31,7 @@ func New() *Parser {
 }
 
 func (p *Parser) Parse(mover TokenMover) (ast2.Ast, error) {
-	p.mover = mover
+	p.mover = newDocMover(mover)
 
 	file, err := p.parseFile()
 	if err != nil {
@@ -37,37 +42,126 @@ func (p *Parser) Parse(mover TokenMover) (ast2.Ast, error) {
 }
 
 func (p *Parser) parseFile() (ast2.File, error) {
-	imports, err := p.parseImports()
+	imports, definitions, err := p.parseDeclarations()
 	if err != nil {
 		return ast2.File{}, errors.Wrap(err, "parse imports")
 	}
 
-	object, err := p.parseObject()
-	if err != nil {
-		return ast2.File{}, errors.Wrap(err, "parse object")
+	// Корнем документа может быть любое выражение: объект, массив, скаляр или ссылка на импорт.
+	value, err := p.parseExpression()
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrTokenMismatch):
+		return ast2.File{}, errors.Wrap(NewErrExpectedNode("expression"), "parse value")
+	default:
+		return ast2.File{}, errors.Wrap(err, "parse value")
 	}
 
-	return ast2.NewFile(imports, object), nil
+	if _, ok := value.(ast2.Spread); ok {
+		return ast2.File{}, errors.Wrap(NewErrExpectedNode("expression"), "parse value")
+	}
+
+	if !p.mover.IsEmpty() {
+		return ast2.File{}, errors.Wrapf(
+			ErrUnexpectedToken,
+			"unexpected %s after document root at %d:%d",
+			p.mover.Token().Type().String(),
+			p.mover.Token().Location().Start().Line(),
+			p.mover.Token().Location().Start().Column(),
+		)
+	}
+
+	file := ast2.NewFile(imports, value)
+	if len(definitions) > 0 {
+		file = file.SetDefinitions(definitions)
+	}
+
+	return file, nil
 }
 
-func (p *Parser) parseImports() ([]ast2.Import, error) {
+// parseDeclarations разбирает импорты и локальные переменные перед корнем документа. Порядок произвольный.
+func (p *Parser) parseDeclarations() ([]ast2.Import, []ast2.Definition, error) {
 	imports := make([]ast2.Import, 0)
-	if p.mover.IsEmpty() {
-		return imports, nil
-	}
+	definitions := make([]ast2.Definition, 0)
 
 	for {
-		imp, err := p.parseImport()
+		if !p.match(token2.Ident) || !p.isDeclaration() {
+			return imports, definitions, nil
+		}
 
-		switch {
-		case err == nil:
-			imports = append(imports, imp)
-		case errors.Is(err, ErrTokenMismatch):
-			return imports, nil
-		default:
-			return imports, errors.Wrap(err, "parse import")
+		if p.isDefinition() {
+			def, err := p.parseDefinition()
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "parse definition")
+			}
+
+			definitions = append(definitions, def)
+
+			continue
 		}
+
+		imp, err := p.parseImport()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "parse import")
+		}
+
+		imports = append(imports, imp)
+	}
+}
+
+// isDeclaration сообщает, начинается ли с идентификатора импорт или локальная переменная, а не корень документа.
+func (p *Parser) isDeclaration() bool {
+	p.mover.SavePoint()
+	defer p.mover.RemoveSavePoint()
+	defer p.mover.ReturnToSavePoint()
+
+	p.mover.Next()
+
+	return p.match(token2.Path, token2.Assign)
+}
+
+func (p *Parser) isDefinition() bool {
+	p.mover.SavePoint()
+	defer p.mover.RemoveSavePoint()
+	defer p.mover.ReturnToSavePoint()
+
+	p.mover.Next()
+
+	return p.match(token2.Assign)
+}
+
+func (p *Parser) parseDefinition() (ast2.Definition, error) {
+	if err := p.require(token2.Ident); err != nil {
+		return ast2.Definition{}, err
+	}
+
+	nameToken := p.mover.Token()
+
+	p.mover.Next()
+
+	if err := p.require(token2.Assign); err != nil {
+		return ast2.Definition{}, err
+	}
+
+	p.mover.Next()
+
+	value, err := p.parseExpression()
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrTokenMismatch):
+		return ast2.Definition{}, NewErrExpectedNode("expression")
+	default:
+		return ast2.Definition{}, errors.Wrap(err, "parse expression")
+	}
+
+	if _, ok := value.(ast2.Spread); ok {
+		return ast2.Definition{}, NewErrExpectedNode("expression")
 	}
+
+	return ast2.NewDefinition(
+		ast2.NewIdent(nameToken.Value().String(), nameToken.Location()),
+		value,
+	), nil
 }
 
 func (p *Parser) parseImport() (ast2.Import, error) {
@@ -85,12 +179,27 @@ func (p *Parser) parseImport() (ast2.Import, error) {
 
 	importPath := p.mover.Token()
 
+	// ?./local.atmc - необязательный импорт.
+	optional := strings.HasPrefix(importPath.Value().String(), optionalImportPrefix)
+
+	// Шаблон выбирает файлы по имени: вложенные директории (**) и шаблоны директорий (./*/) не поддерживаются.
+	path := ast2.NewPath(strings.TrimPrefix(importPath.Value().String(), optionalImportPrefix), importPath.Location())
+	if strings.Contains(path.String(), "**") || (path.IsGlob() && (path.IsDir() || strings.Contains(filepath.Dir(path.String()), "*"))) {
+		return ast2.Import{}, errors.Wrapf(
+			ErrUnexpectedToken,
+			"unsupported import pattern %s at %d:%d",
+			path.String(),
+			importPath.Location().Start().Line(),
+			importPath.Location().Start().Column(),
+		)
+	}
+
 	p.mover.Next()
 
 	return ast2.NewImport(
 		ast2.NewIdent(importName.Value().String(), importName.Location()),
-		ast2.NewPath(importPath.Value().String(), importPath.Location()),
-	), nil
+		path,
+	).SetOptional(optional), nil
 }
 
 func (p *Parser) parseObject() (ast2.Object, error) {
@@ -104,8 +213,14 @@ func (p *Parser) parseObject() (ast2.Object, error) {
 
 	entries := make([]ast2.Entry, 0)
 
-	for !p.match(token2.RBrace) {
-		entry, err := p.parseEntry()
+	for {
+		doc := p.parseDoc()
+
+		if p.match(token2.RBrace) {
+			break
+		}
+
+		entry, err := p.parseEntry(doc)
 		if err != nil {
 			return ast2.Object{}, err
 		}
@@ -133,9 +248,10 @@ func (p *Parser) parseSpread() (ast2.Spread, error) {
 		return ast2.Spread{}, err
 	}
 
-	if err = p.check(token2.Spread); err != nil {
+	// В конце документа токена нет - это тоже не spread, а переменная.
+	if !p.match(token2.Spread) {
 		p.mover.ReturnToSavePoint()
-		return ast2.Spread{}, err
+		return ast2.Spread{}, NewErrTokenMismatch(token2.Spread)
 	}
 
 	s := ast2.NewSpread(
@@ -151,6 +267,65 @@ func (p *Parser) parseSpread() (ast2.Spread, error) {
 	return s, nil
 }
 
+// parseCall разбирает вызов функции: upper(name).
+// Скобка пишется слитно с именем, иначе это переменная и выражение в скобках: [a (b + 1)].
+func (p *Parser) parseCall() (ast2.Call, error) {
+	if err := p.check(token2.Ident); err != nil {
+		return ast2.Call{}, err
+	}
+
+	nameToken := p.mover.Token()
+
+	p.mover.SavePoint()
+	defer p.mover.RemoveSavePoint()
+
+	p.mover.Next()
+
+	if !p.match(token2.LParen) {
+		p.mover.ReturnToSavePoint()
+		return ast2.Call{}, NewErrTokenMismatch(token2.LParen)
+	}
+
+	if p.mover.Token().Location().Start() != nameToken.Location().End() {
+		p.mover.ReturnToSavePoint()
+		return ast2.Call{}, errors.Wrap(NewErrTokenMismatch(token2.LParen), "got: separated left paren")
+	}
+
+	p.mover.Next()
+
+	args := make([]ast2.Expression, 0)
+
+	for !p.match(token2.RParen) {
+		arg, err := p.parseExpression()
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrTokenMismatch):
+			return ast2.Call{}, NewErrExpectedNode("expression")
+		default:
+			return ast2.Call{}, errors.Wrap(err, "parse call argument")
+		}
+
+		if _, ok := arg.(ast2.Spread); ok {
+			return ast2.Call{}, NewErrExpectedNode("expression")
+		}
+
+		args = append(args, arg)
+	}
+
+	call := ast2.NewCall(
+		ast2.NewIdent(nameToken.Value().String(), nameToken.Location()),
+		args,
+		types.NewLocation(
+			nameToken.Location().Start(),
+			p.mover.Token().Location().End(),
+		),
+	)
+
+	p.mover.Next()
+
+	return call, nil
+}
+
 func (p *Parser) parseVar() (ast2.Var, error) {
 	if err := p.check(token2.Ident); err != nil {
 		return ast2.Var{}, err
@@ -168,20 +343,45 @@ func (p *Parser) parseVar() (ast2.Var, error) {
 		),
 	)
 
+	end := p.mover.Token().Location().End()
+
 	p.mover.Next()
 
-	if p.match(token2.Dot) {
-		p.mover.Next()
+	for {
+		// Индекс пишется слитно с предыдущей частью: hosts[0]. Через пробел "[" - начало массива.
+		if p.match(token2.LBracket) && p.mover.Token().Location().Start() == end {
+			index, err := p.parseIndex()
+			if err != nil {
+				return ast2.Var{}, errors.Wrap(err, "parse var")
+			}
 
-		v, err := p.parseVar()
-		switch {
-		case err == nil:
-			idents = append(idents, v.Path()...)
-		case errors.Is(err, ErrTokenMismatch):
+			idents = append(idents, index)
+			end = index.Location().End()
+
+			continue
+		}
+
+		if !p.match(token2.Dot) {
 			break
-		default:
+		}
+
+		p.mover.Next()
+
+		// Начиная со второй части, путь может содержать ключи в кавычках и ключевые слова: common."x-request-id", flags.null.
+		if err := p.require(keyTokens...); err != nil {
 			return ast2.Var{}, errors.Wrap(err, "parse var")
 		}
+
+		idents = append(
+			idents,
+			ast2.NewIdent(
+				p.mover.Token().Value().String(),
+				p.mover.Token().Location(),
+			),
+		)
+		end = p.mover.Token().Location().End()
+
+		p.mover.Next()
 	}
 
 	return ast2.NewVar(
@@ -189,11 +389,30 @@ func (p *Parser) parseVar() (ast2.Var, error) {
 	), nil
 }
 
-func (p *Parser) parseEntry() (ast2.Entry, error) {
+// parseDoc собирает подряд идущие документирующие комментарии перед ключом.
+func (p *Parser) parseDoc() ast2.Doc {
+	if p.mover.IsEmpty() || len(p.mover.Docs()) == 0 {
+		return ast2.Doc{}
+	}
+
+	docs := p.mover.Docs()
+	lines := make([]string, 0, len(docs))
+
+	for _, doc := range docs {
+		lines = append(lines, doc.Value().String())
+	}
+
+	return ast2.NewDoc(
+		strings.Join(lines, "\n"),
+		types.NewLocation(docs[0].Location().Start(), docs[len(docs)-1].Location().End()),
+	)
+}
+
+func (p *Parser) parseEntry(doc ast2.Doc) (ast2.Entry, error) {
 	kv, err := p.parseKV()
 	switch {
 	case err == nil:
-		return kv, nil
+		return kv.SetDoc(doc), nil
 	case errors.Is(err, ErrTokenMismatch):
 	default:
 		return nil, errors.Wrap(err, "parse entry")
@@ -207,11 +426,40 @@ func (p *Parser) parseEntry() (ast2.Entry, error) {
 	return spread, nil
 }
 
+// parseIndex разбирает индекс элемента массива в пути переменной: [0], [-1].
+func (p *Parser) parseIndex() (ast2.Index, error) {
+	start := p.mover.Token().Location().Start()
+
+	p.mover.Next()
+
+	if err := p.require(token2.Int); err != nil {
+		return ast2.Index{}, errors.Wrap(err, "parse index")
+	}
+
+	i, err := ast2.NewInt(p.mover.Token().Value().String(), p.mover.Token().Location())
+	if err != nil {
+		return ast2.Index{}, errors.Wrap(err, "parse index")
+	}
+
+	p.mover.Next()
+
+	if err = p.require(token2.RBracket); err != nil {
+		return ast2.Index{}, errors.Wrap(err, "parse index")
+	}
+
+	end := p.mover.Token().Location().End()
+
+	p.mover.Next()
+
+	return ast2.NewIndex(int(i.Value()), types.NewLocation(start, end)), nil
+}
+
 func (p *Parser) parseKV() (ast2.KV, error) {
 	p.mover.SavePoint()
 	defer p.mover.RemoveSavePoint()
 
-	if err := p.check(token2.Ident); err != nil {
+	// Ключ может быть строкой в кавычках: "x-request-id", "app.kubernetes.io/name", "8080".
+	if err := p.check(keyTokens...); err != nil {
 		return ast2.KV{}, err
 	}
 
@@ -225,13 +473,27 @@ func (p *Parser) parseKV() (ast2.KV, error) {
 
 	p.mover.Next()
 
-	if err := p.check(token2.Colon); err != nil {
+	if p.match(token2.At) {
+		return p.parseAnnotatedKV(key)
+	}
+
+	if err := p.check(token2.Colon, token2.AppendColon, token2.PrependColon, token2.ReplaceColon); err != nil {
 		p.mover.ReturnToSavePoint()
 		return ast2.KV{}, err
 	}
 
+	merge := mergeStrategies[p.mover.Token().Type()]
+
 	p.mover.Next()
 
+	if p.match(token2.Delete) {
+		d := ast2.NewDelete(p.mover.Token().Location())
+
+		p.mover.Next()
+
+		return ast2.NewKV(key, d).SetMerge(merge), nil
+	}
+
 	expr, err := p.parseExpression()
 	switch {
 	case err == nil:
@@ -244,19 +506,292 @@ func (p *Parser) parseKV() (ast2.KV, error) {
 	return ast2.NewKV(
 		key,
 		expr,
+	).SetMerge(merge), nil
+}
+
+// parseAnnotatedKV разбирает ключ с аннотацией слияния: services @merge(name): [...].
+func (p *Parser) parseAnnotatedKV(key ast2.Ident) (ast2.KV, error) {
+	p.mover.Next()
+
+	if err := p.require(token2.Ident); err != nil {
+		return ast2.KV{}, errors.Wrap(err, "parse annotation")
+	}
+
+	if name := p.mover.Token().Value().String(); name != "merge" {
+		return ast2.KV{}, errors.Wrapf(
+			ErrUnexpectedToken,
+			"unknown annotation @%s at %d:%d",
+			name,
+			p.mover.Token().Location().Start().Line(),
+			p.mover.Token().Location().Start().Column(),
+		)
+	}
+
+	p.mover.Next()
+
+	if err := p.require(token2.LParen); err != nil {
+		return ast2.KV{}, errors.Wrap(err, "parse annotation")
+	}
+
+	p.mover.Next()
+
+	if err := p.require(token2.Ident); err != nil {
+		return ast2.KV{}, errors.Wrap(err, "parse annotation")
+	}
+
+	mergeKey := ast2.NewIdent(p.mover.Token().Value().String(), p.mover.Token().Location())
+
+	p.mover.Next()
+
+	if err := p.require(token2.RParen); err != nil {
+		return ast2.KV{}, errors.Wrap(err, "parse annotation")
+	}
+
+	p.mover.Next()
+
+	if err := p.require(token2.Colon); err != nil {
+		return ast2.KV{}, errors.Wrap(err, "parse annotated key")
+	}
+
+	p.mover.Next()
+
+	expr, err := p.parseExpression()
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrTokenMismatch):
+		return ast2.KV{}, NewErrExpectedNode("expression")
+	default:
+		return ast2.KV{}, errors.Wrap(err, "parse expression")
+	}
+
+	return ast2.NewKV(key, expr).SetMergeKey(mergeKey), nil
+}
+
+// keyTokens - токены, которые могут быть ключом объекта.
+// Ключевые слова в позиции ключа - обычные ключи: {null: 1 if: true}.
+var keyTokens = []token2.Type{
+	token2.Ident,
+	token2.String,
+	token2.RawString,
+	token2.Bool,
+	token2.Null,
+	token2.If,
+	token2.Then,
+	token2.Else,
+	token2.Delete,
+}
+
+// mergeStrategies - стратегия слияния по разделителю ключа и значения.
+var mergeStrategies = map[token2.Type]ast2.MergeStrategy{
+	token2.Colon:        ast2.MergeDefault,
+	token2.AppendColon:  ast2.MergeAppend,
+	token2.PrependColon: ast2.MergePrepend,
+	token2.ReplaceColon: ast2.MergeReplace,
+}
+
+// binaryOperator - бинарный оператор и его приоритет: чем больше, тем сильнее связывание.
+type binaryOperator struct {
+	op         ast2.Operator
+	precedence int
+}
+
+var binaryOperators = map[token2.Type]binaryOperator{
+	token2.Or:      {op: ast2.OpOr, precedence: 1},
+	token2.And:     {op: ast2.OpAnd, precedence: 2},
+	token2.Eq:      {op: ast2.OpEq, precedence: 3},
+	token2.NotEq:   {op: ast2.OpNotEq, precedence: 3},
+	token2.Lt:      {op: ast2.OpLess, precedence: 4},
+	token2.LtEq:    {op: ast2.OpLessEq, precedence: 4},
+	token2.Gt:      {op: ast2.OpGreater, precedence: 4},
+	token2.GtEq:    {op: ast2.OpGreaterEq, precedence: 4},
+	token2.Plus:    {op: ast2.OpAdd, precedence: 5},
+	token2.Minus:   {op: ast2.OpSub, precedence: 5},
+	token2.Star:    {op: ast2.OpMul, precedence: 6},
+	token2.Slash:   {op: ast2.OpDiv, precedence: 6},
+	token2.Percent: {op: ast2.OpMod, precedence: 6},
+}
+
+func (p *Parser) parseExpression() (ast2.Expression, error) {
+	return p.parseBinary(1)
+}
+
+// parseBinary разбирает бинарные выражения методом precedence climbing.
+// Все бинарные операторы левоассоциативны.
+func (p *Parser) parseBinary(minPrecedence int) (ast2.Expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	// Spread не может быть операндом.
+	if _, ok := left.(ast2.Spread); ok {
+		return left, nil
+	}
+
+	for !p.mover.IsEmpty() {
+		opToken := p.mover.Token()
+
+		operator, ok := binaryOperators[opToken.Type()]
+		if !ok || operator.precedence < minPrecedence {
+			break
+		}
+
+		p.mover.Next()
+
+		right, err := p.parseBinary(operator.precedence + 1)
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrTokenMismatch):
+			return nil, NewErrExpectedNode("expression")
+		default:
+			return nil, errors.Wrap(err, "parse right operand")
+		}
+
+		left = ast2.NewBinary(operator.op, opToken.Location().Start(), left, right)
+	}
+
+	return left, nil
+}
+
+func (p *Parser) parseUnary() (ast2.Expression, error) {
+	if !p.match(token2.Minus, token2.Bang) {
+		return p.parsePrimary()
+	}
+
+	opToken := p.mover.Token()
+
+	op := ast2.OpSub
+	if opToken.Type() == token2.Bang {
+		op = ast2.OpNot
+	}
+
+	p.mover.Next()
+
+	operand, err := p.parseUnary()
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrTokenMismatch):
+		return nil, NewErrExpectedNode("expression")
+	default:
+		return nil, errors.Wrap(err, "parse operand")
+	}
+
+	return ast2.NewUnary(
+		op,
+		operand,
+		types.NewLocation(
+			opToken.Location().Start(),
+			operand.Location().End(),
+		),
+	), nil
+}
+
+// parseParen разбирает выражение в скобках: (a + b) * 2.
+func (p *Parser) parseParen() (ast2.Expression, error) {
+	if err := p.require(token2.LParen); err != nil {
+		return nil, err
+	}
+
+	p.mover.Next()
+
+	expr, err := p.parseExpression()
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrTokenMismatch):
+		return nil, NewErrExpectedNode("expression")
+	default:
+		return nil, errors.Wrap(err, "parse expression")
+	}
+
+	if err = p.require(token2.RParen); err != nil {
+		return nil, err
+	}
+
+	p.mover.Next()
+
+	return expr, nil
+}
+
+// parseConditional разбирает условное выражение: if cond then a else b.
+// Ветка else обязательна, цепочки записываются как else if ...
+func (p *Parser) parseConditional() (ast2.Conditional, error) {
+	if err := p.require(token2.If); err != nil {
+		return ast2.Conditional{}, err
+	}
+
+	start := p.mover.Token().Location().Start()
+	p.mover.Next()
+
+	cond, err := p.parseBranch("condition")
+	if err != nil {
+		return ast2.Conditional{}, err
+	}
+
+	if err = p.require(token2.Then); err != nil {
+		return ast2.Conditional{}, err
+	}
+
+	p.mover.Next()
+
+	then, err := p.parseBranch("then branch")
+	if err != nil {
+		return ast2.Conditional{}, err
+	}
+
+	if err = p.require(token2.Else); err != nil {
+		return ast2.Conditional{}, err
+	}
+
+	p.mover.Next()
+
+	otherwise, err := p.parseBranch("else branch")
+	if err != nil {
+		return ast2.Conditional{}, err
+	}
+
+	return ast2.NewConditional(
+		cond,
+		then,
+		otherwise,
+		types.NewLocation(start, otherwise.Location().End()),
 	), nil
 }
 
-func (p *Parser) parseExpression() (expr ast2.Expression, err error) {
+func (p *Parser) parseBranch(name string) (ast2.Expression, error) {
+	expr, err := p.parseExpression()
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrTokenMismatch):
+		return nil, NewErrExpectedNode("expression")
+	default:
+		return nil, errors.Wrapf(err, "parse %s", name)
+	}
+
+	if _, ok := expr.(ast2.Spread); ok {
+		return nil, NewErrExpectedNode("expression")
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) parsePrimary() (expr ast2.Expression, err error) {
 	if err = p.require(
 		token2.Ident,
+		token2.LParen,
+		token2.If,
 		token2.LBrace,
 		token2.LBracket,
 		token2.Dollar,
 		token2.String,
+		token2.RawString,
+		token2.MultilineString,
+		token2.TemplateStart,
 		token2.Int,
 		token2.Float,
+		token2.Duration,
+		token2.ByteSize,
 		token2.Bool,
+		token2.Null,
 	); err != nil {
 		return nil, err
 	}
@@ -272,6 +807,15 @@ func (p *Parser) parseExpression() (expr ast2.Expression, err error) {
 			return nil, err
 		}
 
+		expr, err = p.parseCall()
+		switch {
+		case err == nil:
+			return expr, nil
+		case errors.Is(err, ErrTokenMismatch):
+		default:
+			return nil, err
+		}
+
 		expr, err = p.parseVar()
 		if err != nil {
 			return nil, err
@@ -284,6 +828,20 @@ func (p *Parser) parseExpression() (expr ast2.Expression, err error) {
 			return nil, err
 		}
 
+		return expr, nil
+	case token2.LParen:
+		expr, err = p.parseParen()
+		if err != nil {
+			return nil, err
+		}
+
+		return expr, nil
+	case token2.If:
+		expr, err = p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+
 		return expr, nil
 	case token2.LBrace:
 		expr, err = p.parseObject()
@@ -301,7 +859,7 @@ func (p *Parser) parseExpression() (expr ast2.Expression, err error) {
 
 		return expr, nil
 
-	case token2.String:
+	case token2.String, token2.RawString, token2.MultilineString:
 		expr, err = p.parseString()
 		if err != nil {
 			return nil, err
@@ -309,6 +867,14 @@ func (p *Parser) parseExpression() (expr ast2.Expression, err error) {
 
 		return expr, nil
 
+	case token2.TemplateStart:
+		expr, err = p.parseTemplate()
+		if err != nil {
+			return nil, err
+		}
+
+		return expr, nil
+
 	case token2.Float:
 		expr, err = p.parseFloat()
 		if err != nil {
@@ -325,12 +891,35 @@ func (p *Parser) parseExpression() (expr ast2.Expression, err error) {
 
 		return expr, nil
 
+	case token2.Duration:
+		expr, err = p.parseDuration()
+		if err != nil {
+			return nil, err
+		}
+
+		return expr, nil
+
+	case token2.ByteSize:
+		expr, err = p.parseByteSize()
+		if err != nil {
+			return nil, err
+		}
+
+		return expr, nil
+
 	case token2.Bool:
 		expr, err = p.parseBool()
 		if err != nil {
 			return nil, err
 		}
 
+		return expr, nil
+	case token2.Null:
+		expr, err = p.parseNull()
+		if err != nil {
+			return nil, err
+		}
+
 		return expr, nil
 	default:
 		return nil, NewErrUnexpectedToken()
@@ -338,7 +927,7 @@ func (p *Parser) parseExpression() (expr ast2.Expression, err error) {
 }
 
 func (p *Parser) parseString() (ast2.String, error) {
-	if err := p.require(token2.String); err != nil {
+	if err := p.require(token2.String, token2.RawString, token2.MultilineString); err != nil {
 		return ast2.String{}, err
 	}
 
@@ -352,6 +941,75 @@ func (p *Parser) parseString() (ast2.String, error) {
 	return s, nil
 }
 
+// parseTemplate разбирает строку с интерполяцией: литералы и выражения ${var.path} или ${$ENV}.
+func (p *Parser) parseTemplate() (ast2.Template, error) {
+	if err := p.require(token2.TemplateStart); err != nil {
+		return ast2.Template{}, err
+	}
+
+	start := p.mover.Token().Location().Start()
+
+	p.mover.Next()
+
+	parts := make([]ast2.Expression, 0)
+
+	for !p.match(token2.TemplateEnd) {
+		if err := p.require(token2.String, token2.Interpolation); err != nil {
+			return ast2.Template{}, errors.Wrap(err, "parse template")
+		}
+
+		if p.match(token2.String) {
+			s, err := p.parseString()
+			if err != nil {
+				return ast2.Template{}, errors.Wrap(err, "parse string")
+			}
+
+			parts = append(parts, s)
+
+			continue
+		}
+
+		p.mover.Next()
+
+		if err := p.require(token2.Ident, token2.Dollar); err != nil {
+			return ast2.Template{}, errors.Wrap(err, "parse interpolation")
+		}
+
+		var (
+			expr ast2.Expression
+			err  error
+		)
+		if p.match(token2.Dollar) {
+			expr, err = p.parseEnv()
+		} else {
+			expr, err = p.parseVar()
+		}
+		if err != nil {
+			return ast2.Template{}, errors.Wrap(err, "parse interpolation")
+		}
+
+		if err = p.require(token2.RBrace); err != nil {
+			return ast2.Template{}, errors.Wrap(err, "parse interpolation")
+		}
+
+		p.mover.Next()
+
+		parts = append(parts, expr)
+	}
+
+	template := ast2.NewTemplate(
+		parts,
+		types.NewLocation(
+			start,
+			p.mover.Token().Location().End(),
+		),
+	)
+
+	p.mover.Next()
+
+	return template, nil
+}
+
 func (p *Parser) parseBool() (ast2.Bool, error) {
 	if err := p.require(token2.Bool); err != nil {
 		return ast2.Bool{}, err
@@ -370,6 +1028,18 @@ func (p *Parser) parseBool() (ast2.Bool, error) {
 	return b, nil
 }
 
+func (p *Parser) parseNull() (ast2.Null, error) {
+	if err := p.require(token2.Null); err != nil {
+		return ast2.Null{}, err
+	}
+
+	n := ast2.NewNull(p.mover.Token().Location())
+
+	p.mover.Next()
+
+	return n, nil
+}
+
 func (p *Parser) parseInt() (ast2.Int, error) {
 	if err := p.require(token2.Int); err != nil {
 		return ast2.Int{}, err
@@ -406,6 +1076,42 @@ func (p *Parser) parseFloat() (ast2.Float, error) {
 	return f, nil
 }
 
+func (p *Parser) parseDuration() (ast2.Duration, error) {
+	if err := p.require(token2.Duration); err != nil {
+		return ast2.Duration{}, err
+	}
+
+	d, err := ast2.NewDuration(
+		p.mover.Token().Value().String(),
+		p.mover.Token().Location(),
+	)
+	if err != nil {
+		return ast2.Duration{}, errors.Wrap(err, "parse duration")
+	}
+
+	p.mover.Next()
+
+	return d, nil
+}
+
+func (p *Parser) parseByteSize() (ast2.ByteSize, error) {
+	if err := p.require(token2.ByteSize); err != nil {
+		return ast2.ByteSize{}, err
+	}
+
+	b, err := ast2.NewByteSize(
+		p.mover.Token().Value().String(),
+		p.mover.Token().Location(),
+	)
+	if err != nil {
+		return ast2.ByteSize{}, errors.Wrap(err, "parse byte size")
+	}
+
+	p.mover.Next()
+
+	return b, nil
+}
+
 func (p *Parser) parseEnv() (ast2.Env, error) {
 	if err := p.require(token2.Dollar); err != nil {
 		return ast2.Env{}, err
@@ -419,18 +1125,74 @@ func (p *Parser) parseEnv() (ast2.Env, error) {
 		return ast2.Env{}, err
 	}
 
+	nameToken := p.mover.Token()
+	end := nameToken.Location().End()
+
+	p.mover.Next()
+
+	// Тип пишется слитно с именем: $PORT:int.
+	var typ ast2.EnvType
+	if p.match(token2.Colon) && p.mover.Token().Location().Start() == end {
+		p.mover.Next()
+
+		if err := p.require(token2.Ident); err != nil {
+			return ast2.Env{}, errors.Wrap(err, "parse env type")
+		}
+
+		t, err := ast2.NewEnvType(p.mover.Token().Value().String(), p.mover.Token().Location())
+		if err != nil {
+			return ast2.Env{}, errors.Wrap(err, "parse env type")
+		}
+
+		typ = t
+		end = p.mover.Token().Location().End()
+
+		p.mover.Next()
+	}
+
+	// Маркер обязательности пишется слитно с именем (или типом): $DB_PASSWORD!
+	required := p.match(token2.Bang) && p.mover.Token().Location().Start() == end
+	if required {
+		end = p.mover.Token().Location().End()
+
+		p.mover.Next()
+	}
+
+	var def ast2.Expression
+	if !required && p.match(token2.Coalesce) {
+		p.mover.Next()
+
+		// Значение по умолчанию - операнд, а не целое выражение: $STAGE ?? "dev" == "prod" - это ($STAGE ?? "dev") == "prod".
+		expr, err := p.parseUnary()
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrTokenMismatch):
+			return ast2.Env{}, NewErrExpectedNode("expression")
+		default:
+			return ast2.Env{}, errors.Wrap(err, "parse env default")
+		}
+
+		if _, ok := expr.(ast2.Spread); ok {
+			return ast2.Env{}, NewErrExpectedNode("expression")
+		}
+
+		def = expr
+		end = expr.Location().End()
+	}
+
 	env := ast2.NewEnv(
 		ast2.NewIdent(
-			p.mover.Token().Value().String(),
-			p.mover.Token().Location(),
+			nameToken.Value().String(),
+			nameToken.Location(),
 		),
 		types.NewLocation(
 			dollarToken.Location().Start(),
-			p.mover.Token().Location().End(),
+			end,
 		),
-	)
-
-	p.mover.Next()
+	).
+		SetType(typ).
+		SetDefault(def).
+		SetRequired(required)
 
 	return env, nil
 }
@@ -446,7 +1208,11 @@ func (p *Parser) parseArray() (ast2.Array, error) {
 
 	elements := make([]ast2.Expression, 0)
 
-	for !p.match(token2.RBracket) {
+	for {
+		if p.match(token2.RBracket) {
+			break
+		}
+
 		expr, err := p.parseExpression()
 		switch {
 		case err == nil:
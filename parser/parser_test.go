@@ -43,6 +43,59 @@ func TestParser_Parse(t *testing.T) {
 				),
 			),
 		},
+		{
+			name: "with imports and definitions",
+			tokens: []token2.Token{
+				// host = "db.internal"
+				token2.New(token2.Ident, "host", types.Location{}),
+				token2.New(token2.Assign, "", types.Location{}),
+				token2.New(token2.String, "db.internal", types.Location{}),
+				// common ./common.atmc
+				token2.New(token2.Ident, "common", types.Location{}),
+				token2.New(token2.Path, "./common.atmc", types.Location{}),
+				// port = common.port + 1
+				token2.New(token2.Ident, "port", types.Location{}),
+				token2.New(token2.Assign, "", types.Location{}),
+				token2.New(token2.Ident, "common", types.Location{}),
+				token2.New(token2.Dot, "", types.Location{}),
+				token2.New(token2.Ident, "port", types.Location{}),
+				token2.New(token2.Plus, "", types.Location{}),
+				token2.New(token2.Int, "1", types.Location{}),
+				token2.New(token2.LBrace, "", types.Location{}),
+				token2.New(token2.RBrace, "", types.Location{}),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{
+						ast2.NewImport(
+							ast2.NewIdent("common", types.Location{}),
+							ast2.NewPath("./common.atmc", types.Location{}),
+						),
+					},
+					ast2.NewObject(
+						[]ast2.Entry{},
+						types.Location{},
+					),
+				).SetDefinitions([]ast2.Definition{
+					ast2.NewDefinition(
+						ast2.NewIdent("host", types.Location{}),
+						ast2.NewString("db.internal", types.Location{}),
+					),
+					ast2.NewDefinition(
+						ast2.NewIdent("port", types.Location{}),
+						ast2.NewBinary(
+							ast2.OpAdd,
+							types.Position{},
+							ast2.NewVar([]ast2.Ident{
+								ast2.NewIdent("common", types.Location{}),
+								ast2.NewIdent("port", types.Location{}),
+							}),
+							testast.MustNewInt(t, "1"),
+						),
+					),
+				}),
+			),
+		},
 		{
 			name: "without import and empty object",
 			tokens: []token2.Token{
@@ -549,6 +602,676 @@ func TestParser_Parse(t *testing.T) {
 				),
 			),
 		},
+		{
+			name: "with doc comments",
+			tokens: []token2.Token{
+				token2.New(token2.DocComment, "Root doc is ignored", types.Location{}),
+				token2.New(token2.LBrace, "", types.Location{}),
+
+				token2.New(token2.DocComment, "Number of workers.", types.Location{}),
+				token2.New(token2.DocComment, "Must be positive.", types.Location{}),
+				token2.New(token2.Ident, "key1", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.LBracket, "", types.Location{}),
+				token2.New(token2.DocComment, "Ignored in array", types.Location{}),
+				token2.New(token2.Int, "123", types.Location{}),
+				token2.New(token2.RBracket, "", types.Location{}),
+
+				token2.New(token2.Ident, "key2", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.Int, "321", types.Location{}),
+
+				token2.New(token2.DocComment, "Dangling doc", types.Location{}),
+				token2.New(token2.RBrace, "", types.Location{}),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{},
+					ast2.NewObject(
+						[]ast2.Entry{
+							ast2.NewKV(
+								ast2.NewIdent("key1", types.Location{}),
+								ast2.NewArray(
+									[]ast2.Expression{
+										testast.MustNewInt(t, "123"),
+									},
+									types.Location{},
+								),
+							).SetDoc(ast2.NewDoc("Number of workers.\nMust be positive.", types.Location{})),
+							ast2.NewKV(
+								ast2.NewIdent("key2", types.Location{}),
+								testast.MustNewInt(t, "321"),
+							),
+						},
+						types.Location{},
+					),
+				),
+			),
+		},
+		{
+			name: "with quoted keys",
+			tokens: []token2.Token{
+				token2.New(token2.LBrace, "", types.Location{}),
+
+				token2.New(token2.String, "x-request-id", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.Ident, "common", types.Location{}),
+				token2.New(token2.Dot, "", types.Location{}),
+				token2.New(token2.String, "app.kubernetes.io/name", types.Location{}),
+
+				token2.New(token2.String, "8080", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.Bool, "true", types.Location{}),
+
+				token2.New(token2.RBrace, "", types.Location{}),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{},
+					ast2.NewObject(
+						[]ast2.Entry{
+							ast2.NewKV(
+								ast2.NewIdent("x-request-id", types.Location{}),
+								ast2.NewVar(
+									[]ast2.Ident{
+										ast2.NewIdent("common", types.Location{}),
+										ast2.NewIdent("app.kubernetes.io/name", types.Location{}),
+									},
+								),
+							),
+							ast2.NewKV(
+								ast2.NewIdent("8080", types.Location{}),
+								testast.MustNewBool(t, "true"),
+							),
+						},
+						types.Location{},
+					),
+				),
+			),
+		},
+		{
+			name: "with env type, default and required marker",
+			tokens: []token2.Token{
+				token2.New(token2.LBrace, "", types.Location{}),
+
+				token2.New(token2.Ident, "port", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.Dollar, "", types.Location{}),
+				token2.New(token2.Ident, "PORT", types.Location{}),
+				token2.New(token2.Coalesce, "", types.Location{}),
+				token2.New(token2.Int, "8080", types.Location{}),
+
+				token2.New(token2.Ident, "password", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.Dollar, "", types.Location{}),
+				token2.New(token2.Ident, "DB_PASSWORD", types.Location{}),
+				token2.New(token2.Bang, "", types.Location{}),
+
+				token2.New(token2.Ident, "workers", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.Dollar, "", types.Location{}),
+				token2.New(token2.Ident, "WORKERS", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.Ident, "int", types.Location{}),
+				token2.New(token2.Bang, "", types.Location{}),
+
+				token2.New(token2.RBrace, "", types.Location{}),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{},
+					ast2.NewObject(
+						[]ast2.Entry{
+							ast2.NewKV(
+								ast2.NewIdent("port", types.Location{}),
+								ast2.NewEnv(
+									ast2.NewIdent("PORT", types.Location{}),
+									types.Location{},
+								).SetDefault(testast.MustNewInt(t, "8080")),
+							),
+							ast2.NewKV(
+								ast2.NewIdent("password", types.Location{}),
+								ast2.NewEnv(
+									ast2.NewIdent("DB_PASSWORD", types.Location{}),
+									types.Location{},
+								).SetRequired(true),
+							),
+							ast2.NewKV(
+								ast2.NewIdent("workers", types.Location{}),
+								ast2.NewEnv(
+									ast2.NewIdent("WORKERS", types.Location{}),
+									types.Location{},
+								).
+									SetType(ast2.EnvTypeInt).
+									SetRequired(true),
+							),
+						},
+						types.Location{},
+					),
+				),
+			),
+		},
+		{
+			name: "with expressions",
+			tokens: []token2.Token{
+				token2.New(token2.LBrace, "", types.Location{}),
+
+				// a: x + y * -2 == 4 && !(z || w)
+				token2.New(token2.Ident, "a", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.Ident, "x", types.Location{}),
+				token2.New(token2.Plus, "", types.Location{}),
+				token2.New(token2.Ident, "y", types.Location{}),
+				token2.New(token2.Star, "", types.Location{}),
+				token2.New(token2.Minus, "", types.Location{}),
+				token2.New(token2.Int, "2", types.Location{}),
+				token2.New(token2.Eq, "", types.Location{}),
+				token2.New(token2.Int, "4", types.Location{}),
+				token2.New(token2.And, "", types.Location{}),
+				token2.New(token2.Bang, "", types.Location{}),
+				token2.New(token2.LParen, "", types.Location{}),
+				token2.New(token2.Ident, "z", types.Location{}),
+				token2.New(token2.Or, "", types.Location{}),
+				token2.New(token2.Ident, "w", types.Location{}),
+				token2.New(token2.RParen, "", types.Location{}),
+
+				// b: 10 - 3 - 2
+				token2.New(token2.Ident, "b", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.Int, "10", types.Location{}),
+				token2.New(token2.Minus, "", types.Location{}),
+				token2.New(token2.Int, "3", types.Location{}),
+				token2.New(token2.Minus, "", types.Location{}),
+				token2.New(token2.Int, "2", types.Location{}),
+
+				token2.New(token2.RBrace, "", types.Location{}),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{},
+					ast2.NewObject(
+						[]ast2.Entry{
+							ast2.NewKV(
+								ast2.NewIdent("a", types.Location{}),
+								ast2.NewBinary(
+									ast2.OpAnd,
+									types.Position{},
+									ast2.NewBinary(
+										ast2.OpEq,
+										types.Position{},
+										ast2.NewBinary(
+											ast2.OpAdd,
+											types.Position{},
+											ast2.NewVar([]ast2.Ident{ast2.NewIdent("x", types.Location{})}),
+											ast2.NewBinary(
+												ast2.OpMul,
+												types.Position{},
+												ast2.NewVar([]ast2.Ident{ast2.NewIdent("y", types.Location{})}),
+												ast2.NewUnary(ast2.OpSub, testast.MustNewInt(t, "2"), types.Location{}),
+											),
+										),
+										testast.MustNewInt(t, "4"),
+									),
+									ast2.NewUnary(
+										ast2.OpNot,
+										ast2.NewBinary(
+											ast2.OpOr,
+											types.Position{},
+											ast2.NewVar([]ast2.Ident{ast2.NewIdent("z", types.Location{})}),
+											ast2.NewVar([]ast2.Ident{ast2.NewIdent("w", types.Location{})}),
+										),
+										types.Location{},
+									),
+								),
+							),
+							ast2.NewKV(
+								ast2.NewIdent("b", types.Location{}),
+								ast2.NewBinary(
+									ast2.OpSub,
+									types.Position{},
+									ast2.NewBinary(
+										ast2.OpSub,
+										types.Position{},
+										testast.MustNewInt(t, "10"),
+										testast.MustNewInt(t, "3"),
+									),
+									testast.MustNewInt(t, "2"),
+								),
+							),
+						},
+						types.Location{},
+					),
+				),
+			),
+		},
+		{
+			name: "with conditional",
+			tokens: []token2.Token{
+				token2.New(token2.LBrace, "", types.Location{}),
+
+				// a: if x == 1 then -1 else if y then 2 else 3
+				token2.New(token2.Ident, "a", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.If, "", types.Location{}),
+				token2.New(token2.Ident, "x", types.Location{}),
+				token2.New(token2.Eq, "", types.Location{}),
+				token2.New(token2.Int, "1", types.Location{}),
+				token2.New(token2.Then, "", types.Location{}),
+				token2.New(token2.Minus, "", types.Location{}),
+				token2.New(token2.Int, "1", types.Location{}),
+				token2.New(token2.Else, "", types.Location{}),
+				token2.New(token2.If, "", types.Location{}),
+				token2.New(token2.Ident, "y", types.Location{}),
+				token2.New(token2.Then, "", types.Location{}),
+				token2.New(token2.Int, "2", types.Location{}),
+				token2.New(token2.Else, "", types.Location{}),
+				token2.New(token2.Int, "3", types.Location{}),
+
+				token2.New(token2.RBrace, "", types.Location{}),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{},
+					ast2.NewObject(
+						[]ast2.Entry{
+							ast2.NewKV(
+								ast2.NewIdent("a", types.Location{}),
+								ast2.NewConditional(
+									ast2.NewBinary(
+										ast2.OpEq,
+										types.Position{},
+										ast2.NewVar([]ast2.Ident{ast2.NewIdent("x", types.Location{})}),
+										testast.MustNewInt(t, "1"),
+									),
+									ast2.NewUnary(ast2.OpSub, testast.MustNewInt(t, "1"), types.Location{}),
+									ast2.NewConditional(
+										ast2.NewVar([]ast2.Ident{ast2.NewIdent("y", types.Location{})}),
+										testast.MustNewInt(t, "2"),
+										testast.MustNewInt(t, "3"),
+										types.Location{},
+									),
+									types.Location{},
+								),
+							),
+						},
+						types.Location{},
+					),
+				),
+			),
+		},
+		{
+			name: "with delete",
+			tokens: []token2.Token{
+				token2.New(token2.LBrace, "", types.Location{}),
+
+				// common...
+				token2.New(token2.Ident, "common", types.Location{}),
+				token2.New(token2.Spread, "", types.Location{}),
+
+				// tracing: delete
+				token2.New(token2.Ident, "tracing", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.Delete, "", types.Location{}),
+
+				token2.New(token2.RBrace, "", types.Location{}),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{},
+					ast2.NewObject(
+						[]ast2.Entry{
+							ast2.NewSpread(ast2.NewVar([]ast2.Ident{ast2.NewIdent("common", types.Location{})}), types.Location{}),
+							ast2.NewKV(
+								ast2.NewIdent("tracing", types.Location{}),
+								ast2.NewDelete(types.Location{}),
+							),
+						},
+						types.Location{},
+					),
+				),
+			),
+		},
+		{
+			name: "with merge strategies",
+			tokens: []token2.Token{
+				token2.New(token2.LBrace, "", types.Location{}),
+
+				// level +: x
+				token2.New(token2.Ident, "level", types.Location{}),
+				token2.New(token2.AppendColon, "", types.Location{}),
+				token2.New(token2.Ident, "x", types.Location{}),
+
+				// brokers ^: x
+				token2.New(token2.Ident, "brokers", types.Location{}),
+				token2.New(token2.PrependColon, "", types.Location{}),
+				token2.New(token2.Ident, "x", types.Location{}),
+
+				// logging =: x
+				token2.New(token2.Ident, "logging", types.Location{}),
+				token2.New(token2.ReplaceColon, "", types.Location{}),
+				token2.New(token2.Ident, "x", types.Location{}),
+
+				token2.New(token2.RBrace, "", types.Location{}),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{},
+					ast2.NewObject(
+						[]ast2.Entry{
+							ast2.NewKV(
+								ast2.NewIdent("level", types.Location{}),
+								ast2.NewVar([]ast2.Ident{ast2.NewIdent("x", types.Location{})}),
+							).SetMerge(ast2.MergeAppend),
+							ast2.NewKV(
+								ast2.NewIdent("brokers", types.Location{}),
+								ast2.NewVar([]ast2.Ident{ast2.NewIdent("x", types.Location{})}),
+							).SetMerge(ast2.MergePrepend),
+							ast2.NewKV(
+								ast2.NewIdent("logging", types.Location{}),
+								ast2.NewVar([]ast2.Ident{ast2.NewIdent("x", types.Location{})}),
+							).SetMerge(ast2.MergeReplace),
+						},
+						types.Location{},
+					),
+				),
+			),
+		},
+		{
+			name: "with merge annotation",
+			tokens: []token2.Token{
+				token2.New(token2.LBrace, "", types.Location{}),
+
+				// services @merge(name): x
+				token2.New(token2.Ident, "services", types.Location{}),
+				token2.New(token2.At, "", types.Location{}),
+				token2.New(token2.Ident, "merge", types.Location{}),
+				token2.New(token2.LParen, "", types.Location{}),
+				token2.New(token2.Ident, "name", types.Location{}),
+				token2.New(token2.RParen, "", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.Ident, "x", types.Location{}),
+
+				token2.New(token2.RBrace, "", types.Location{}),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{},
+					ast2.NewObject(
+						[]ast2.Entry{
+							ast2.NewKV(
+								ast2.NewIdent("services", types.Location{}),
+								ast2.NewVar([]ast2.Ident{ast2.NewIdent("x", types.Location{})}),
+							).SetMergeKey(ast2.NewIdent("name", types.Location{})),
+						},
+						types.Location{},
+					),
+				),
+			),
+		},
+		{
+			name: "with index",
+			tokens: []token2.Token{
+				token2.New(token2.LBrace, "", types.Location{}),
+
+				// a: clusters[-1].hosts[0]
+				token2.New(token2.Ident, "a", types.NewLocation(types.NewPosition(1, 1, 1), types.NewPosition(1, 2, 2))),
+				token2.New(token2.Colon, "", types.NewLocation(types.NewPosition(1, 2, 2), types.NewPosition(1, 3, 3))),
+				token2.New(token2.Ident, "clusters", types.NewLocation(types.NewPosition(1, 4, 4), types.NewPosition(1, 12, 12))),
+				token2.New(token2.LBracket, "", types.NewLocation(types.NewPosition(1, 12, 12), types.NewPosition(1, 13, 13))),
+				token2.New(token2.Int, "-1", types.NewLocation(types.NewPosition(1, 13, 13), types.NewPosition(1, 15, 15))),
+				token2.New(token2.RBracket, "", types.NewLocation(types.NewPosition(1, 15, 15), types.NewPosition(1, 16, 16))),
+				token2.New(token2.Dot, "", types.NewLocation(types.NewPosition(1, 16, 16), types.NewPosition(1, 17, 17))),
+				token2.New(token2.Ident, "hosts", types.NewLocation(types.NewPosition(1, 17, 17), types.NewPosition(1, 22, 22))),
+				token2.New(token2.LBracket, "", types.NewLocation(types.NewPosition(1, 22, 22), types.NewPosition(1, 23, 23))),
+				token2.New(token2.Int, "0", types.NewLocation(types.NewPosition(1, 23, 23), types.NewPosition(1, 24, 24))),
+				token2.New(token2.RBracket, "", types.NewLocation(types.NewPosition(1, 24, 24), types.NewPosition(1, 25, 25))),
+
+				// b: [x [0]] - через пробел это массив, а не индекс
+				token2.New(token2.Ident, "b", types.NewLocation(types.NewPosition(2, 1, 27), types.NewPosition(2, 2, 28))),
+				token2.New(token2.Colon, "", types.NewLocation(types.NewPosition(2, 2, 28), types.NewPosition(2, 3, 29))),
+				token2.New(token2.LBracket, "", types.NewLocation(types.NewPosition(2, 4, 30), types.NewPosition(2, 5, 31))),
+				token2.New(token2.Ident, "x", types.NewLocation(types.NewPosition(2, 5, 31), types.NewPosition(2, 6, 32))),
+				token2.New(token2.LBracket, "", types.NewLocation(types.NewPosition(2, 7, 33), types.NewPosition(2, 8, 34))),
+				token2.New(token2.Int, "0", types.NewLocation(types.NewPosition(2, 8, 34), types.NewPosition(2, 9, 35))),
+				token2.New(token2.RBracket, "", types.NewLocation(types.NewPosition(2, 9, 35), types.NewPosition(2, 10, 36))),
+				token2.New(token2.RBracket, "", types.NewLocation(types.NewPosition(2, 10, 36), types.NewPosition(2, 11, 37))),
+
+				token2.New(token2.RBrace, "", types.Location{}),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{},
+					ast2.NewObject(
+						[]ast2.Entry{
+							ast2.NewKV(
+								ast2.NewIdent("a", types.NewLocation(types.NewPosition(1, 1, 1), types.NewPosition(1, 2, 2))),
+								ast2.NewVar([]ast2.Ident{
+									ast2.NewIdent("clusters", types.NewLocation(types.NewPosition(1, 4, 4), types.NewPosition(1, 12, 12))),
+									ast2.NewIndex(-1, types.NewLocation(types.NewPosition(1, 12, 12), types.NewPosition(1, 16, 16))),
+									ast2.NewIdent("hosts", types.NewLocation(types.NewPosition(1, 17, 17), types.NewPosition(1, 22, 22))),
+									ast2.NewIndex(0, types.NewLocation(types.NewPosition(1, 22, 22), types.NewPosition(1, 25, 25))),
+								}),
+							),
+							ast2.NewKV(
+								ast2.NewIdent("b", types.NewLocation(types.NewPosition(2, 1, 27), types.NewPosition(2, 2, 28))),
+								ast2.NewArray(
+									[]ast2.Expression{
+										ast2.NewVar([]ast2.Ident{
+											ast2.NewIdent("x", types.NewLocation(types.NewPosition(2, 5, 31), types.NewPosition(2, 6, 32))),
+										}),
+										ast2.NewArray(
+											[]ast2.Expression{
+												testast.MustNewIntWithLocation(t, "0", types.NewLocation(types.NewPosition(2, 8, 34), types.NewPosition(2, 9, 35))),
+											},
+											types.NewLocation(types.NewPosition(2, 7, 33), types.NewPosition(2, 10, 36)),
+										),
+									},
+									types.NewLocation(types.NewPosition(2, 4, 30), types.NewPosition(2, 11, 37)),
+								),
+							),
+						},
+						types.Location{},
+					),
+				),
+			),
+		},
+		{
+			name: "with array root",
+			tokens: []token2.Token{
+				token2.New(token2.Ident, "common", types.Location{}),
+				token2.New(token2.Path, "./common.atmc", types.Location{}),
+
+				// [common.level "fatal"]
+				token2.New(token2.LBracket, "", types.NewLocation(types.NewPosition(2, 1, 21), types.NewPosition(2, 2, 22))),
+				token2.New(token2.Ident, "common", types.NewLocation(types.NewPosition(2, 2, 22), types.NewPosition(2, 8, 28))),
+				token2.New(token2.Dot, "", types.NewLocation(types.NewPosition(2, 8, 28), types.NewPosition(2, 9, 29))),
+				token2.New(token2.Ident, "level", types.NewLocation(types.NewPosition(2, 9, 29), types.NewPosition(2, 14, 34))),
+				token2.New(token2.String, "fatal", types.NewLocation(types.NewPosition(2, 15, 35), types.NewPosition(2, 22, 42))),
+				token2.New(token2.RBracket, "", types.NewLocation(types.NewPosition(2, 22, 42), types.NewPosition(2, 23, 43))),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{
+						ast2.NewImport(
+							ast2.NewIdent("common", types.Location{}),
+							ast2.NewPath("./common.atmc", types.Location{}),
+						),
+					},
+					ast2.NewArray(
+						[]ast2.Expression{
+							ast2.NewVar([]ast2.Ident{
+								ast2.NewIdent("common", types.NewLocation(types.NewPosition(2, 2, 22), types.NewPosition(2, 8, 28))),
+								ast2.NewIdent("level", types.NewLocation(types.NewPosition(2, 9, 29), types.NewPosition(2, 14, 34))),
+							}),
+							ast2.NewString("fatal", types.NewLocation(types.NewPosition(2, 15, 35), types.NewPosition(2, 22, 42))),
+						},
+						types.NewLocation(types.NewPosition(2, 1, 21), types.NewPosition(2, 23, 43)),
+					),
+				),
+			),
+		},
+		{
+			name: "with optional import",
+			tokens: []token2.Token{
+				token2.New(token2.Ident, "local", types.Location{}),
+				token2.New(token2.Path, "?./local.atmc", types.Location{}),
+				token2.New(token2.LBrace, "", types.Location{}),
+				token2.New(token2.Ident, "local", types.Location{}),
+				token2.New(token2.Spread, "", types.Location{}),
+				token2.New(token2.RBrace, "", types.Location{}),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{
+						ast2.NewImport(
+							ast2.NewIdent("local", types.Location{}),
+							ast2.NewPath("./local.atmc", types.Location{}),
+						).SetOptional(true),
+					},
+					ast2.NewObject(
+						[]ast2.Entry{
+							ast2.NewSpread(
+								ast2.NewVar([]ast2.Ident{
+									ast2.NewIdent("local", types.Location{}),
+								}),
+								types.Location{},
+							),
+						},
+						types.Location{},
+					),
+				),
+			),
+		},
+		{
+			name: "with scalar root",
+			tokens: []token2.Token{
+				token2.New(token2.Int, "8080", types.NewLocation(types.NewPosition(1, 1, 1), types.NewPosition(1, 5, 5))),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{},
+					testast.MustNewIntWithLocation(t, "8080", types.NewLocation(types.NewPosition(1, 1, 1), types.NewPosition(1, 5, 5))),
+				),
+			),
+		},
+		{
+			name: "with call",
+			tokens: []token2.Token{
+				token2.New(token2.LBrace, "", types.Location{}),
+
+				// a: join(split(x, ",") "-")
+				token2.New(token2.Ident, "a", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.Ident, "join", types.Location{}),
+				token2.New(token2.LParen, "", types.Location{}),
+				token2.New(token2.Ident, "split", types.Location{}),
+				token2.New(token2.LParen, "", types.Location{}),
+				token2.New(token2.Ident, "x", types.Location{}),
+				token2.New(token2.String, ",", types.Location{}),
+				token2.New(token2.RParen, "", types.Location{}),
+				token2.New(token2.String, "-", types.Location{}),
+				token2.New(token2.RParen, "", types.Location{}),
+
+				// b: len()
+				token2.New(token2.Ident, "b", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.Ident, "len", types.Location{}),
+				token2.New(token2.LParen, "", types.Location{}),
+				token2.New(token2.RParen, "", types.Location{}),
+
+				token2.New(token2.RBrace, "", types.Location{}),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{},
+					ast2.NewObject(
+						[]ast2.Entry{
+							ast2.NewKV(
+								ast2.NewIdent("a", types.Location{}),
+								ast2.NewCall(
+									ast2.NewIdent("join", types.Location{}),
+									[]ast2.Expression{
+										ast2.NewCall(
+											ast2.NewIdent("split", types.Location{}),
+											[]ast2.Expression{
+												ast2.NewVar([]ast2.Ident{ast2.NewIdent("x", types.Location{})}),
+												ast2.NewString(",", types.Location{}),
+											},
+											types.Location{},
+										),
+										ast2.NewString("-", types.Location{}),
+									},
+									types.Location{},
+								),
+							),
+							ast2.NewKV(
+								ast2.NewIdent("b", types.Location{}),
+								ast2.NewCall(
+									ast2.NewIdent("len", types.Location{}),
+									[]ast2.Expression{},
+									types.Location{},
+								),
+							),
+						},
+						types.Location{},
+					),
+				),
+			),
+		},
+		{
+			name: "with interpolation",
+			tokens: []token2.Token{
+				token2.New(token2.LBrace, "", types.Location{}),
+
+				token2.New(token2.Ident, "dsn", types.Location{}),
+				token2.New(token2.Colon, "", types.Location{}),
+				token2.New(token2.TemplateStart, "", types.Location{}),
+				token2.New(token2.String, "postgres://", types.Location{}),
+				token2.New(token2.Interpolation, "", types.Location{}),
+				token2.New(token2.Ident, "db", types.Location{}),
+				token2.New(token2.Dot, "", types.Location{}),
+				token2.New(token2.Ident, "host", types.Location{}),
+				token2.New(token2.RBrace, "", types.Location{}),
+				token2.New(token2.String, ":", types.Location{}),
+				token2.New(token2.Interpolation, "", types.Location{}),
+				token2.New(token2.Dollar, "", types.Location{}),
+				token2.New(token2.Ident, "PG_PORT", types.Location{}),
+				token2.New(token2.RBrace, "", types.Location{}),
+				token2.New(token2.TemplateEnd, "", types.Location{}),
+
+				token2.New(token2.RBrace, "", types.Location{}),
+			},
+			expected: ast2.NewAst(
+				ast2.NewFile(
+					[]ast2.Import{},
+					ast2.NewObject(
+						[]ast2.Entry{
+							ast2.NewKV(
+								ast2.NewIdent("dsn", types.Location{}),
+								ast2.NewTemplate(
+									[]ast2.Expression{
+										ast2.NewString("postgres://", types.Location{}),
+										ast2.NewVar(
+											[]ast2.Ident{
+												ast2.NewIdent("db", types.Location{}),
+												ast2.NewIdent("host", types.Location{}),
+											},
+										),
+										ast2.NewString(":", types.Location{}),
+										ast2.NewEnv(
+											ast2.NewIdent("PG_PORT", types.Location{}),
+											types.Location{},
+										),
+									},
+									types.Location{},
+								),
+							),
+						},
+						types.Location{},
+					),
+				),
+			),
+		},
 	}
 
 	for _, tc := range testCases {
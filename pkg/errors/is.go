@@ -5,3 +5,7 @@ import "errors"
 func Is(err error, target error) bool {
 	return errors.Is(err, target)
 }
+
+func As(err error, target any) bool {
+	return errors.As(err, target)
+}
@@ -0,0 +1,112 @@
+package processor
+
+import (
+	"regexp"
+	"strings"
+
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/pkg/errors"
+)
+
+var dotEnvKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// readDotEnv читает .env файл в объект строк: KEY=value, export KEY=value, комментарии через #.
+// Значения в одинарных кавычках берутся как есть, в двойных - с escape-последовательностями \n, \t, \", \\.
+// Переменные в значениях не подставляются.
+func readDotEnv(data []byte) (linkedast.Expression, error) {
+	kvs := make([]linkedast.KV, 0)
+	indexByKey := make(map[string]int)
+
+	for i, line := range strings.Split(string(data), "\n") {
+		lineNum := i + 1
+		line = strings.TrimRight(line, "\r")
+
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := len(line) - len(trimmed)
+		trimmed = strings.TrimPrefix(trimmed, "export ")
+
+		eq := strings.IndexByte(trimmed, '=')
+		if eq < 0 {
+			return nil, newErrInvalidModule("dotenv", lineNum, indent+1, "expected KEY=VALUE")
+		}
+
+		key := strings.TrimSpace(trimmed[:eq])
+		if !dotEnvKey.MatchString(key) {
+			return nil, newErrInvalidModule("dotenv", lineNum, indent+1, "invalid key %q", key)
+		}
+
+		rawValue := trimmed[eq+1:]
+		valueColumn := len(line) - len(rawValue) + 1
+
+		value, err := dotEnvValue(rawValue)
+		if err != nil {
+			return nil, newErrInvalidModule("dotenv", lineNum, valueColumn, "%s", err.Error())
+		}
+
+		kv := linkedast.NewKV(linkedast.NewIdent(key), linkedast.NewString(value))
+
+		// Повторный ключ заменяет значение, как при загрузке в окружение.
+		if idx, ok := indexByKey[key]; ok {
+			kvs[idx] = kv
+			continue
+		}
+
+		indexByKey[key] = len(kvs)
+		kvs = append(kvs, kv)
+	}
+
+	return linkedast.NewObject(kvs), nil
+}
+
+func dotEnvValue(raw string) (string, error) {
+	raw = strings.TrimLeft(raw, " \t")
+
+	if raw == "" {
+		return "", nil
+	}
+
+	switch quote := raw[0]; quote {
+	case '\'', '"':
+		end := closingQuote(raw, quote)
+		if end < 0 {
+			return "", errors.New("unterminated quoted value")
+		}
+
+		if rest := strings.TrimSpace(raw[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", errors.New("unexpected characters after quoted value")
+		}
+
+		if quote == '\'' {
+			return raw[1:end], nil
+		}
+
+		return dotEnvReplacer.Replace(raw[1:end]), nil
+	default:
+		// Комментарий в значении без кавычек начинается с пробела перед #.
+		if i := strings.Index(raw, " #"); i >= 0 {
+			raw = raw[:i]
+		}
+
+		return strings.TrimSpace(raw), nil
+	}
+}
+
+var dotEnvReplacer = strings.NewReplacer(`\n`, "\n", `\r`, "\r", `\t`, "\t", `\"`, `"`, `\\`, `\`)
+
+// closingQuote индекс закрывающей кавычки. В двойных кавычках кавычку можно экранировать.
+func closingQuote(s string, quote byte) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+
+	return -1
+}
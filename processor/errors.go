@@ -0,0 +1,16 @@
+package processor
+
+import (
+	"fmt"
+
+	"github.com/atmxlab/atmc/pkg/errors"
+)
+
+var (
+	ErrInvalidModule = errors.New("invalid module")
+)
+
+// newErrInvalidModule ошибка разбора файла другого формата с позицией в этом файле.
+func newErrInvalidModule(format string, line, column int, msg string, a ...any) error {
+	return errors.Wrapf(ErrInvalidModule, "%s: %s at %d:%d", format, fmt.Sprintf(msg, a...), line, column)
+}
@@ -10,8 +10,11 @@ import (
 
 //go:generate mock OS
 type OS interface {
+	// ReadFile возвращает ошибку errors.ErrNotFound, если файла нет.
 	ReadFile(string) ([]byte, error)
 	AbsPath(baseDir, relPath string) (string, error)
+	// Glob возвращает пути файлов, подходящих под шаблон, в порядке сортировки.
+	Glob(pattern string) ([]string, error)
 	EnvVariables() map[string]string
 }
 
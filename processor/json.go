@@ -0,0 +1,30 @@
+package processor
+
+import (
+	"encoding/json"
+
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/pkg/errors"
+)
+
+func readJSON(data []byte) (linkedast.Expression, error) {
+	// encoding/json сообщает смещение синтаксической ошибки только при полной проверке документа.
+	var raw json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			// Offset указывает на байт после невалидного символа.
+			line, column := position(data, int(syntaxErr.Offset)-1)
+			return nil, newErrInvalidModule("json", line, column, "%s", syntaxErr.Error())
+		}
+
+		return nil, errors.Wrapf(ErrInvalidModule, "json: %s", err.Error())
+	}
+
+	exp, err := linkedast.DecodeJSON(data)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidModule, "json: %s", err.Error())
+	}
+
+	return exp, nil
+}
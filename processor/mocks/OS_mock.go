@@ -67,6 +67,21 @@ func (mr *MockOSMockRecorder) EnvVariables() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnvVariables", reflect.TypeOf((*MockOS)(nil).EnvVariables))
 }
 
+// Glob mocks base method.
+func (m *MockOS) Glob(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Glob", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Glob indicates an expected call of Glob.
+func (mr *MockOSMockRecorder) Glob(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Glob", reflect.TypeOf((*MockOS)(nil).Glob), arg0)
+}
+
 // ReadFile mocks base method.
 func (m *MockOS) ReadFile(arg0 string) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"path/filepath"
+
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/pkg/errors"
+)
+
+// atmcExt расширение atmc файлов - их можно импортировать из директории вместе с файлами других форматов.
+const atmcExt = ".atmc"
+
+// moduleReader читает файл другого формата сразу в linker/ast: такой файл не нужно лексить, парсить и линковать.
+type moduleReader func(data []byte) (linkedast.Expression, error)
+
+// moduleReaders читатели файлов по расширению. Остальные файлы считаются atmc.
+var moduleReaders = map[string]moduleReader{
+	".json": readJSON,
+	".yaml": readYAML,
+	".yml":  readYAML,
+	".toml": readTOML,
+	".env":  readDotEnv,
+}
+
+func moduleReaderFor(path string) (moduleReader, bool) {
+	read, ok := moduleReaders[filepath.Ext(path)]
+	return read, ok
+}
+
+func (p *Processor) makeModule(path, content string, read moduleReader) (linkedast.Ast, error) {
+	root, err := read([]byte(content))
+	if err != nil {
+		return linkedast.Ast{}, errors.Wrapf(err, "file: %s", path)
+	}
+
+	return linkedast.NewAst(root), nil
+}
+
+// position переводит смещение в байтах в строку и колонку. Колонки считаются в байтах с 1.
+func position(data []byte, offset int) (int, int) {
+	line, column := 1, 1
+
+	for i := 0; i < offset && i < len(data); i++ {
+		if data[i] == '\n' {
+			line++
+			column = 1
+			continue
+		}
+
+		column++
+	}
+
+	return line, column
+}
@@ -2,6 +2,8 @@ package processor
 
 import (
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/atmxlab/atmc/lexer/tokenmover"
 	"github.com/atmxlab/atmc/linker"
@@ -17,6 +19,8 @@ type Processor struct {
 	analyzer  Analyzer
 	linker    Linker
 	astByPath map[string]ast2.WithPath
+	// Файлы других форматов (json, yaml, toml, .env) - они сразу читаются в linker/ast.
+	modules map[string]linkedast.Ast
 }
 
 func New(lexer Lexer, parser Parser, analyzer Analyzer, linker Linker, os OS) *Processor {
@@ -27,6 +31,7 @@ func New(lexer Lexer, parser Parser, analyzer Analyzer, linker Linker, os OS) *P
 		analyzer:  analyzer,
 		linker:    linker,
 		astByPath: make(map[string]ast2.WithPath),
+		modules:   make(map[string]linkedast.Ast),
 	}
 }
 
@@ -36,14 +41,20 @@ func (p *Processor) Process(path string) (linkedast.Ast, error) {
 		return linkedast.Ast{}, errors.Wrap(err, "get abs path")
 	}
 
-	if err = p.process(absPath, newEmptyImportStack()); err != nil {
+	if err = p.process(absPath, newEmptyImportStack(), false); err != nil {
 		return linkedast.Ast{}, errors.Wrap(err, "process")
 	}
 
+	if module, ok := p.modules[absPath]; ok {
+		return module, nil
+	}
+
 	linkedAst, err := p.linker.Link(linker.LinkParam{
 		MainAst:   p.astByPath[absPath],
 		ASTByPath: p.astByPath,
+		Modules:   p.modules,
 		Env:       p.os.EnvVariables(),
+		FS:        p.os,
 	})
 	if err != nil {
 		return linkedast.Ast{}, errors.Wrap(err, "linker.Link")
@@ -52,23 +63,51 @@ func (p *Processor) Process(path string) (linkedast.Ast, error) {
 	return linkedAst, nil
 }
 
-func (p *Processor) process(path string, iStack importStack) error {
+// process разбирает файл и его импорты. Если необязательного файла нет, он пропускается:
+// линкер подставит вместо него пустой объект.
+func (p *Processor) process(path string, iStack importStack, optional bool) error {
 	if _, ok := p.astByPath[path]; ok {
 		return nil
 	}
 
+	if _, ok := p.modules[path]; ok {
+		return nil
+	}
+
 	if _, ok := iStack[path]; ok {
 		return errors.New("import cycle detected")
 	}
 
+	code, err := p.readFileContent(path)
+	switch {
+	case err == nil:
+	case optional && errors.Is(err, errors.ErrNotFound):
+		return nil
+	default:
+		return errors.Wrap(err, "read file content")
+	}
+
+	// Файлы других форматов не содержат импортов, поэтому циклов через них не бывает.
+	if read, ok := moduleReaderFor(path); ok {
+		module, err := p.makeModule(path, code, read)
+		if err != nil {
+			return errors.Wrap(err, "make module")
+		}
+
+		p.modules[path] = module
+
+		return nil
+	}
+
 	iStack[path] = struct{}{}
 
-	madeAST, err := p.makeAst(path)
+	madeAST, err := p.makeAst(path, code)
 	if err != nil {
 		return errors.Wrap(err, "make ast")
 	}
 
 	importPathByRelPath := make(map[string]string, len(madeAST.Imports()))
+	importPathsByRelPath := make(map[string][]string)
 
 	for _, imp := range madeAST.Imports() {
 		importPath, err := p.os.AbsPath(filepath.Dir(path), imp.Path().String())
@@ -76,24 +115,73 @@ func (p *Processor) process(path string, iStack importStack) error {
 			return errors.Wrap(err, "get abs path")
 		}
 
-		importPathByRelPath[imp.Path().String()] = importPath
+		if !imp.Path().IsGlob() && !imp.Path().IsDir() {
+			importPathByRelPath[imp.Path().String()] = importPath
+
+			if err := p.process(importPath, iStack.Clone(), imp.Optional()); err != nil {
+				return errors.Wrap(err, "process import")
+			}
 
-		if err := p.process(importPath, iStack.Clone()); err != nil {
-			return errors.Wrap(err, "process import")
+			continue
+		}
+
+		importPaths, err := p.globImport(imp.Path(), importPath)
+		if err != nil {
+			return errors.Wrapf(err, "expand import %s", imp.Path().String())
+		}
+
+		// Пустой результат скорее всего опечатка в шаблоне - допустим только для необязательного импорта.
+		if len(importPaths) == 0 && !imp.Optional() {
+			return errors.NotFoundf("no files match import %s", imp.Path().String())
+		}
+
+		importPathsByRelPath[imp.Path().String()] = importPaths
+
+		for _, importPath := range importPaths {
+			if err := p.process(importPath, iStack.Clone(), false); err != nil {
+				return errors.Wrap(err, "process import")
+			}
 		}
 	}
 
-	p.astByPath[path] = ast2.NewWithPath(madeAST, path, importPathByRelPath)
+	p.astByPath[path] = ast2.NewWithPath(madeAST, path, importPathByRelPath).SetImportPaths(importPathsByRelPath)
 
 	return nil
 }
 
-func (p *Processor) makeAst(path string) (ast2.Ast, error) {
-	code, err := p.readFileContent(path)
+// globImport возвращает файлы glob-импорта или импорта директории в порядке сортировки.
+// Из директории берутся atmc файлы и файлы поддерживаемых форматов.
+// Файлы без имени, например .env, пропускаются: ключом в объекте импорта служит имя файла без расширения.
+func (p *Processor) globImport(relPath ast2.Path, absPath string) ([]string, error) {
+	pattern := absPath
+	if relPath.IsDir() {
+		pattern = filepath.Join(absPath, "*")
+	}
+
+	matches, err := p.os.Glob(pattern)
 	if err != nil {
-		return ast2.Ast{}, errors.Wrap(err, "read file content")
+		return nil, errors.Wrap(err, "glob")
 	}
 
+	sort.Strings(matches)
+
+	paths := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if strings.TrimSuffix(filepath.Base(match), filepath.Ext(match)) == "" {
+			continue
+		}
+
+		if _, ok := moduleReaderFor(match); relPath.IsDir() && !ok && filepath.Ext(match) != atmcExt {
+			continue
+		}
+
+		paths = append(paths, match)
+	}
+
+	return paths, nil
+}
+
+func (p *Processor) makeAst(path, code string) (ast2.Ast, error) {
 	tokens, err := p.lexer.Tokenize(code)
 	if err != nil {
 		return ast2.Ast{}, errors.Wrap(err, "tokenize")
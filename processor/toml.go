@@ -0,0 +1,220 @@
+package processor
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// readTOML читает документ TOML 1.0. Разбор и проверку повторных определений делает go-toml,
+// порядок ключей берется из разбора документа. Даты и время становятся строками в формате RFC 3339.
+// inf и nan не поддерживаются: для них нет значений ни в конфиге, ни в JSON.
+func readTOML(data []byte) (linkedast.Expression, error) {
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		line, column := tomlErrorPosition(data, err)
+		return nil, newErrInvalidModule("toml", line, column, "%s", strings.TrimPrefix(err.Error(), "toml: "))
+	}
+
+	order, err := tomlKeyOrder(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return tomlValue(doc, nil, order)
+}
+
+// tomlErrorPosition позиция ошибки разбора. Синтаксические ошибки go-toml приходят с позицией,
+// а повторные определения ключей и таблиц - без нее: тогда документ проверяется по префиксам,
+// и ошибкой считается выражение, на котором префикс перестает читаться.
+func tomlErrorPosition(data []byte, err error) (int, int) {
+	var decodeErr *toml.DecodeError
+	if errors.As(err, &decodeErr) {
+		return decodeErr.Position()
+	}
+
+	var p unstable.Parser
+	p.Reset(data)
+
+	var starts []unstable.Position
+
+	for p.NextExpression() {
+		expr := p.Expression()
+		if expr.Kind != unstable.KeyValue && expr.Kind != unstable.Table && expr.Kind != unstable.ArrayTable {
+			continue
+		}
+
+		it := expr.Key()
+		it.Next()
+
+		starts = append(starts, p.Shape(p.Range(it.Node().Data)).Start)
+	}
+
+	for i, start := range starts {
+		end := len(data)
+		if i+1 < len(starts) {
+			// Выражения TOML занимают отдельные строки, поэтому префикс обрезается по началу строки следующего.
+			end = bytes.LastIndexByte(data[:starts[i+1].Offset], '\n') + 1
+		}
+
+		var doc map[string]any
+		if toml.Unmarshal(data[:end], &doc) != nil {
+			return start.Line, start.Column
+		}
+	}
+
+	return 1, 1
+}
+
+// tomlKeyOrder номера ключей в порядке объявления по полному пути ключа: go-toml декодирует таблицы в map.
+// Элементы массивов не различаются - у ключей всех таблиц [[name]] общий путь.
+func tomlKeyOrder(data []byte) (map[string]int, error) {
+	w := tomlWalker{order: make(map[string]int)}
+	w.parser.Reset(data)
+
+	var table []string
+
+	for w.parser.NextExpression() {
+		expr := w.parser.Expression()
+
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			table = w.key(nil, expr.Key())
+		case unstable.KeyValue:
+			if err := w.keyValue(table, expr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := w.parser.Error(); err != nil {
+		return nil, errors.Wrapf(ErrInvalidModule, "toml: %s", err.Error())
+	}
+
+	return w.order, nil
+}
+
+type tomlWalker struct {
+	parser unstable.Parser
+	order  map[string]int
+}
+
+// key дописывает части ключа к пути и запоминает каждый промежуточный путь.
+func (w *tomlWalker) key(path []string, it unstable.Iterator) []string {
+	full := append([]string{}, path...)
+
+	for it.Next() {
+		full = append(full, string(it.Node().Data))
+
+		if _, ok := w.order[tomlPath(full)]; !ok {
+			w.order[tomlPath(full)] = len(w.order)
+		}
+	}
+
+	return full
+}
+
+func (w *tomlWalker) keyValue(table []string, node *unstable.Node) error {
+	return w.value(w.key(table, node.Key()), node.Value())
+}
+
+func (w *tomlWalker) value(path []string, node *unstable.Node) error {
+	switch node.Kind {
+	case unstable.Float:
+		if special := strings.TrimLeft(string(node.Data), "+-"); special == "inf" || special == "nan" {
+			start := w.parser.Shape(w.parser.Range(node.Data)).Start
+			return newErrInvalidModule("toml", start.Line, start.Column, "unsupported float %s", node.Data)
+		}
+	case unstable.InlineTable:
+		for it := node.Children(); it.Next(); {
+			if err := w.keyValue(path, it.Node()); err != nil {
+				return err
+			}
+		}
+	case unstable.Array:
+		for it := node.Children(); it.Next(); {
+			if err := w.value(path, it.Node()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func tomlPath(path []string) string {
+	return strings.Join(path, "\x00")
+}
+
+func tomlValue(v any, path []string, order map[string]int) (linkedast.Expression, error) {
+	switch val := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+		sort.SliceStable(keys, func(i, j int) bool {
+			return tomlKeyIndex(order, path, keys[i]) < tomlKeyIndex(order, path, keys[j])
+		})
+
+		kvs := make([]linkedast.KV, 0, len(keys))
+		for _, key := range keys {
+			exp, err := tomlValue(val[key], append(append([]string{}, path...), key), order)
+			if err != nil {
+				return nil, err
+			}
+
+			kvs = append(kvs, linkedast.NewKV(linkedast.NewIdent(key), exp))
+		}
+
+		return linkedast.NewObject(kvs), nil
+	case []any:
+		elements := make([]linkedast.Expression, 0, len(val))
+		for _, elem := range val {
+			exp, err := tomlValue(elem, path, order)
+			if err != nil {
+				return nil, err
+			}
+
+			elements = append(elements, exp)
+		}
+
+		return linkedast.NewArray(elements), nil
+	case string:
+		return linkedast.NewString(val), nil
+	case bool:
+		return linkedast.NewBool(val), nil
+	case int64:
+		return linkedast.NewInt(val), nil
+	case float64:
+		return linkedast.NewFloat(val), nil
+	case time.Time:
+		return linkedast.NewString(val.Format(time.RFC3339Nano)), nil
+	case toml.LocalDate:
+		return linkedast.NewString(val.String()), nil
+	case toml.LocalTime:
+		return linkedast.NewString(val.String()), nil
+	case toml.LocalDateTime:
+		return linkedast.NewString(val.String()), nil
+	default:
+		return nil, errors.Wrapf(ErrInvalidModule, "toml: unsupported value %T at %s", v, strings.Join(path, "."))
+	}
+}
+
+// tomlKeyIndex номер ключа в порядке объявления. Ключи, которых нет в разборе, идут последними.
+func tomlKeyIndex(order map[string]int, path []string, key string) int {
+	if i, ok := order[tomlPath(append(append([]string{}, path...), key))]; ok {
+		return i
+	}
+
+	return math.MaxInt
+}
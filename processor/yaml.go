@@ -0,0 +1,199 @@
+package processor
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlMaxAliasNodes сколько узлов могут добавить раскрытые якоря.
+// Вложенные якоря раскрываются экспоненциально, поэтому без ограничения небольшой файл может занять всю память.
+const yamlMaxAliasNodes = 100_000
+
+// readYAML читает первый документ YAML. Якоря раскрываются, ключ << сливает объекты, как spread.
+func readYAML(data []byte) (linkedast.Expression, error) {
+	var doc yaml.Node
+
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			// Пустой файл - пустой объект, как пустой values.yaml.
+			return linkedast.NewObject([]linkedast.KV{}), nil
+		}
+
+		return nil, errors.Wrapf(ErrInvalidModule, "%s", err.Error())
+	}
+
+	return new(yamlReader).value(&doc)
+}
+
+type yamlReader struct {
+	// Глубина раскрытия якорей и количество узлов, которые они добавили.
+	aliasDepth int
+	aliasNodes int
+}
+
+func (r *yamlReader) value(node *yaml.Node) (linkedast.Expression, error) {
+	if r.aliasDepth > 0 {
+		r.aliasNodes++
+		if r.aliasNodes > yamlMaxAliasNodes {
+			return nil, newErrInvalidModule("yaml", node.Line, node.Column, "excessive aliasing")
+		}
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return linkedast.NewNull(), nil
+		}
+
+		return r.value(node.Content[0])
+	case yaml.AliasNode:
+		r.aliasDepth++
+		defer func() { r.aliasDepth-- }()
+
+		return r.value(node.Alias)
+	case yaml.MappingNode:
+		return r.object(node)
+	case yaml.SequenceNode:
+		elements := make([]linkedast.Expression, 0, len(node.Content))
+		for _, elem := range node.Content {
+			exp, err := r.value(elem)
+			if err != nil {
+				return nil, err
+			}
+
+			elements = append(elements, exp)
+		}
+
+		return linkedast.NewArray(elements), nil
+	case yaml.ScalarNode:
+		return yamlScalar(node)
+	default:
+		return nil, newErrInvalidModule("yaml", node.Line, node.Column, "unsupported node")
+	}
+}
+
+func (r *yamlReader) object(node *yaml.Node) (linkedast.Object, error) {
+	kvs := make([]linkedast.KV, 0, len(node.Content)/2)
+	// Ключ из << может быть переопределен явным ключом ниже - тогда значение заменяется на месте.
+	indexByKey := make(map[string]int)
+
+	set := func(kv linkedast.KV) {
+		if i, ok := indexByKey[kv.Key().String()]; ok {
+			kvs[i] = kv
+			return
+		}
+
+		indexByKey[kv.Key().String()] = len(kvs)
+		kvs = append(kvs, kv)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+
+		if keyNode.Kind != yaml.ScalarNode {
+			return linkedast.Object{}, newErrInvalidModule("yaml", keyNode.Line, keyNode.Column, "expected scalar key")
+		}
+
+		if keyNode.ShortTag() == "!!merge" {
+			merged, err := r.mergeSources(valueNode)
+			if err != nil {
+				return linkedast.Object{}, err
+			}
+
+			// Явные ключи объекта важнее слитых - они добавляются только если ключа еще нет.
+			for _, kv := range merged {
+				if _, ok := indexByKey[kv.Key().String()]; !ok {
+					set(kv)
+				}
+			}
+
+			continue
+		}
+
+		value, err := r.value(valueNode)
+		if err != nil {
+			return linkedast.Object{}, errors.Wrapf(err, "key %s", keyNode.Value)
+		}
+
+		set(linkedast.NewKV(linkedast.NewIdent(keyNode.Value), value))
+	}
+
+	return linkedast.NewObject(kvs), nil
+}
+
+// mergeSources ключи объектов из значения <<: объект или список объектов. Первый объект в списке важнее.
+func (r *yamlReader) mergeSources(node *yaml.Node) ([]linkedast.KV, error) {
+	sources := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		sources = node.Content
+	}
+
+	kvs := make([]linkedast.KV, 0)
+	seen := make(map[string]struct{})
+
+	for _, source := range sources {
+		exp, err := r.value(source)
+		if err != nil {
+			return nil, err
+		}
+
+		obj, ok := exp.(linkedast.Object)
+		if !ok {
+			return nil, newErrInvalidModule("yaml", source.Line, source.Column, "expected mapping to merge")
+		}
+
+		for _, kv := range obj.KV() {
+			if _, ok := seen[kv.Key().String()]; ok {
+				continue
+			}
+
+			seen[kv.Key().String()] = struct{}{}
+			kvs = append(kvs, kv)
+		}
+	}
+
+	return kvs, nil
+}
+
+func yamlScalar(node *yaml.Node) (linkedast.Expression, error) {
+	switch node.ShortTag() {
+	case "!!null":
+		return linkedast.NewNull(), nil
+	case "!!bool":
+		var b bool
+		if err := node.Decode(&b); err != nil {
+			return nil, newErrInvalidModule("yaml", node.Line, node.Column, "invalid bool %s", node.Value)
+		}
+
+		return linkedast.NewBool(b), nil
+	case "!!int":
+		var i int64
+		if err := node.Decode(&i); err != nil {
+			return nil, newErrInvalidModule("yaml", node.Line, node.Column, "invalid int %s", node.Value)
+		}
+
+		return linkedast.NewInt(i), nil
+	case "!!float":
+		// Для бесконечности и NaN нет значений ни в конфиге, ни в JSON.
+		switch strings.ToLower(node.Value) {
+		case ".inf", "+.inf", "-.inf", ".nan":
+			return nil, newErrInvalidModule("yaml", node.Line, node.Column, "unsupported float %s", node.Value)
+		}
+
+		f, err := strconv.ParseFloat(strings.ReplaceAll(node.Value, "_", ""), 64)
+		if err != nil {
+			return nil, newErrInvalidModule("yaml", node.Line, node.Column, "invalid float %s", node.Value)
+		}
+
+		return linkedast.NewFloat(f), nil
+	default:
+		// Строки, даты и остальные теги остаются строками в исходном виде.
+		return linkedast.NewString(node.Value), nil
+	}
+}
@@ -30,6 +30,14 @@ func (s *Scanner) Scan(t any) error {
 		if err := s.mapCompiler.Compile(v, s.ast); err != nil {
 			return errors.Wrap(err, "mapCompiler.Compile")
 		}
+	case *[]any:
+		if err := s.mapCompiler.CompileSlice(v, s.ast); err != nil {
+			return errors.Wrap(err, "mapCompiler.CompileSlice")
+		}
+	case *any:
+		if err := s.mapCompiler.CompileValue(v, s.ast); err != nil {
+			return errors.Wrap(err, "mapCompiler.CompileValue")
+		}
 	default:
 		if err := s.structCompiler.Compile(v, s.ast); err != nil {
 			return errors.Wrap(err, "structCompiler.Compile")
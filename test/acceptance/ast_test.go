@@ -139,7 +139,7 @@ func TestAstGenerate(t *testing.T) {
 							types.NewPosition(12, 2, 145),
 							types.NewPosition(12, 8, 151),
 						)),
-						ast2.NewString(`test string \"escaped`, types.NewLocation(
+						ast2.NewString(`test string "escaped`, types.NewLocation(
 							types.NewPosition(12, 10, 153),
 							types.NewPosition(12, 33, 176),
 						)),
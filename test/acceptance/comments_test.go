@@ -0,0 +1,130 @@
+package acceptance
+
+import (
+	"testing"
+
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_Comments(t *testing.T) {
+	t.Parallel()
+
+	t.Run("doc_comments_are_attached_to_keys", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+common ./common.atmc
+
+/*
+	Конфиг prod окружения.
+*/
+{
+	common...
+	/// Количество воркеров outbox.
+	/// Должно быть больше нуля.
+	workers: 10
+	logging: {
+		level: "error" /* переопределяем без документации */
+	}
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`{
+	/// Настройки логирования.
+	logging: {
+		/// Минимальный уровень логов.
+		level: "info"
+	}
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					DocKV(
+						"Настройки логирования.",
+						"logging",
+						testlinkedast.NewObjectBuilder().
+							DocKV("Минимальный уровень логов.", "level", linkedast.NewString("error")).
+							Build(),
+					).
+					DocKV("Количество воркеров outbox.\nДолжно быть больше нуля.", "workers", linkedast.NewInt(10))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("doc_comments_without_key_are_ignored", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+/// Заголовок файла.
+base = 2 + /// множитель
+	3
+
+{
+	a: /// значение
+		1
+	b: base * /// коэффициент
+		2
+	/// Список портов.
+	ports: [
+		/// основной
+		8080
+	]
+	/// висячий комментарий
+}
+/// конец файла
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("a", linkedast.NewInt(1)).
+					KV2("b", linkedast.NewInt(10)).
+					DocKV(
+						"Список портов.",
+						"ports",
+						testlinkedast.NewArrayBuilder().
+							Element(linkedast.NewInt(8080)).
+							Build(),
+					)
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+}
@@ -0,0 +1,181 @@
+package acceptance
+
+import (
+	"testing"
+
+	"github.com/atmxlab/atmc/linker"
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/parser"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_Conditional(t *testing.T) {
+	t.Parallel()
+
+	mainFilePath := "/home/user/config.atmc"
+	content := `
+common ./common.atmc
+
+{
+	replicas: if $STAGE == "prod" then 5 else 1
+	log_level: if $STAGE == "prod" then "warn" else if $STAGE == "stage" then "info" else "debug"
+	database: if $STAGE == "prod" then {
+		host: "db.prod"
+		password: $DB_PASSWORD!
+	} else common.database
+	workers: (if common.big then 8 else 2) * 2
+}
+`
+	commonContent := `{
+	big: true
+	database: {
+		host: "localhost"
+	}
+}`
+
+	testCases := []struct {
+		name     string
+		stage    string
+		expected linkedast.Ast
+	}{
+		{
+			name:  "prod",
+			stage: "prod",
+			expected: testlinkedast.NewBuilder().
+				Object(func(ob *testlinkedast.ObjectBuilder) {
+					ob.
+						KV2("replicas", linkedast.NewInt(5)).
+						KV2("log_level", linkedast.NewString("warn")).
+						KV2(
+							"database",
+							testlinkedast.NewObjectBuilder().
+								KV2("host", linkedast.NewString("db.prod")).
+								KV2("password", linkedast.NewString("secret")).
+								Build(),
+						).
+						KV2("workers", linkedast.NewInt(16))
+				}).
+				Build(),
+		},
+		{
+			name:  "stage",
+			stage: "stage",
+			expected: testlinkedast.NewBuilder().
+				Object(func(ob *testlinkedast.ObjectBuilder) {
+					ob.
+						KV2("replicas", linkedast.NewInt(1)).
+						KV2("log_level", linkedast.NewString("info")).
+						KV2(
+							"database",
+							testlinkedast.NewObjectBuilder().
+								KV2("host", linkedast.NewString("localhost")).
+								Build(),
+						).
+						KV2("workers", linkedast.NewInt(16))
+				}).
+				Build(),
+		},
+		{
+			name:  "dev",
+			stage: "dev",
+			expected: testlinkedast.NewBuilder().
+				Object(func(ob *testlinkedast.ObjectBuilder) {
+					ob.
+						KV2("replicas", linkedast.NewInt(1)).
+						KV2("log_level", linkedast.NewString("debug")).
+						KV2(
+							"database",
+							testlinkedast.NewObjectBuilder().
+								KV2("host", linkedast.NewString("localhost")).
+								Build(),
+						).
+						KV2("workers", linkedast.NewInt(16))
+				}).
+				Build(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			osBuilder := testos.NewOSBuilder().
+				File(func(fb *testos.FileBuilder) {
+					fb.
+						Path(mainFilePath).
+						Content(content)
+				}).
+				File(func(fb *testos.FileBuilder) {
+					fb.
+						Path("/home/user/common.atmc").
+						Content(commonContent)
+				}).
+				Env(func(eb *testos.EnvBuilder) {
+					eb.
+						Key("STAGE").
+						Value(tc.stage)
+				})
+
+			// Обязательная переменная нужна только в выбранной ветке.
+			if tc.stage == "prod" {
+				osBuilder.Env(func(eb *testos.EnvBuilder) {
+					eb.
+						Key("DB_PASSWORD").
+						Value("secret")
+				})
+			}
+
+			app := test.NewApp(t, test.WithOS(osBuilder.Build()))
+
+			a, err := app.Processor().Process(mainFilePath)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, a)
+		})
+	}
+
+	t.Run("condition_not_bool", func(t *testing.T) {
+		t.Parallel()
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	replicas: if $STAGE then 5 else 1
+}`)
+			}).
+			Env(func(eb *testos.EnvBuilder) {
+				eb.
+					Key("STAGE").
+					Value("prod")
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrInvalidCondition)
+		require.ErrorContains(t, err, "expected bool, got string at /home/user/config.atmc:2:14")
+	})
+
+	t.Run("without_else", func(t *testing.T) {
+		t.Parallel()
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{replicas: if true then 5}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, parser.ErrUnexpectedToken)
+		require.ErrorContains(t, err, "expected tokens: [else]")
+	})
+}
@@ -0,0 +1,214 @@
+package acceptance
+
+import (
+	"testing"
+
+	"github.com/atmxlab/atmc/analyzer"
+	"github.com/atmxlab/atmc/linker"
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_Definitions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("division", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+total = 10
+half = total/2
+third = total /3
+common ./common.atmc
+
+{
+	half: half
+	third: third
+	limit: common.limit/2
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`{limit: 100}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("half", linkedast.NewInt(5)).
+					KV2("third", linkedast.NewInt(3)).
+					KV2("limit", linkedast.NewInt(50))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("local_variables", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+/// Адрес базы используется в нескольких местах.
+dsn = "postgres://${host}:${port}/app"
+host = "db.internal"
+common ./common.atmc
+port = common.port + 1
+pool = {
+	size: 10
+	timeout: 5s
+}
+
+{
+	primary: {
+		host: host
+		port: port
+		dsn: dsn
+	}
+	replica: {
+		host: "replica." + host
+		pool...
+	}
+	pool_size: pool.size
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`
+host = "common.internal"
+
+{
+	port: 5432
+	host: host
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2(
+						"primary",
+						testlinkedast.NewObjectBuilder().
+							KV2("host", linkedast.NewString("db.internal")).
+							KV2("port", linkedast.NewInt(5433)).
+							KV2("dsn", linkedast.NewString("postgres://db.internal:5433/app")).
+							Build(),
+					).
+					KV2(
+						"replica",
+						testlinkedast.NewObjectBuilder().
+							KV2("host", linkedast.NewString("replica.db.internal")).
+							KV2("size", linkedast.NewInt(10)).
+							KV2("timeout", linkedast.NewDuration(5e9)).
+							Build(),
+					).
+					KV2("pool_size", linkedast.NewInt(10))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`a = b + 1
+b = a * 2
+
+{value: a}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrCyclicDefinition)
+		require.ErrorContains(t, err, "a -> b -> a at /home/user/config.atmc:1:0")
+	})
+
+	t.Run("unused", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`host = "db.internal"
+
+{value: 1}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, analyzer.ErrUnusedVariable)
+	})
+
+	t.Run("duplicates_import", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`common ./common.atmc
+common = 1
+
+{value: common}`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`{}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, analyzer.ErrDuplicateVariable)
+		require.ErrorContains(t, err, "common at 2:0")
+	})
+}
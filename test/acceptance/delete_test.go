@@ -0,0 +1,241 @@
+package acceptance
+
+import (
+	"testing"
+
+	"github.com/atmxlab/atmc/linker"
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_Delete(t *testing.T) {
+	t.Parallel()
+
+	t.Run("delete_inherited_keys", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+common ./common.atmc
+
+{
+	common...
+	tracing: delete
+	logging: {
+		sampling: delete
+	}
+	metrics: delete
+	metrics: {
+		port: 9100
+	}
+	unknown: delete
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`
+{
+	service: "api"
+	tracing: {
+		endpoint: "http://jaeger:14268"
+	}
+	logging: {
+		level: "info"
+		sampling: 0.5
+	}
+	metrics: {
+		path: "/metrics"
+	}
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("service", linkedast.NewString("api")).
+					KV2(
+						"logging",
+						testlinkedast.NewObjectBuilder().
+							KV2("level", linkedast.NewString("info")).
+							Build(),
+					).
+					KV2(
+						"metrics",
+						testlinkedast.NewObjectBuilder().
+							KV2("port", linkedast.NewInt(9100)).
+							Build(),
+					)
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("delete_from_spread_import", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+common ./common.atmc
+overlay ./overlay.atmc
+
+{
+	common...
+	overlay...
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`
+{
+	service: "api"
+	tracing: {
+		endpoint: "http://jaeger:14268"
+	}
+	logging: {
+		level: "info"
+		sampling: 0.5
+	}
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/overlay.atmc").
+					Content(`
+{
+	tracing: delete
+	logging: {
+		sampling: delete
+	}
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("service", linkedast.NewString("api")).
+					KV2(
+						"logging",
+						testlinkedast.NewObjectBuilder().
+							KV2("level", linkedast.NewString("info")).
+							Build(),
+					)
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("reference_to_deleted_key", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+common ./common.atmc
+
+{
+	common...
+	tracing: delete
+	endpoint: root.tracing
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`{tracing: "http://jaeger:14268"}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrNotFoundVariable)
+		require.ErrorContains(t, err, "expected: root.tracing")
+	})
+
+	t.Run("key_named_delete", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+common ./common.atmc
+
+{
+	permissions: {
+		common.permissions...
+		delete: delete
+		write: common.permissions.delete
+	}
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`{permissions: {read: true delete: false}}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.KV2(
+					"permissions",
+					testlinkedast.NewObjectBuilder().
+						KV2("read", linkedast.NewBool(true)).
+						KV2("write", linkedast.NewBool(false)).
+						Build(),
+				)
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+}
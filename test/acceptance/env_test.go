@@ -0,0 +1,379 @@
+package acceptance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atmxlab/atmc/linker"
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	parserast "github.com/atmxlab/atmc/parser/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_Env(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults_and_required", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+{
+	port: $PORT ?? 8080
+	host: $HOST ?? "localhost"
+	empty: $EMPTY ?? $FALLBACK ?? "default"
+	password: $DB_PASSWORD!
+	dsn: "${$DB_USER ?? "admin"}:${$DB_PASSWORD!}"
+	hosts: [$HOST ?? "a" $PORT ?? 1]
+}
+`)
+			}).
+			Env(func(eb *testos.EnvBuilder) {
+				eb.
+					Key("HOST").
+					Value("example.com")
+			}).
+			Env(func(eb *testos.EnvBuilder) {
+				eb.
+					Key("EMPTY").
+					Value("")
+			}).
+			Env(func(eb *testos.EnvBuilder) {
+				eb.
+					Key("DB_PASSWORD").
+					Value("qwerty")
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("port", linkedast.NewInt(8080)).
+					KV2("host", linkedast.NewString("example.com")).
+					KV2("empty", linkedast.NewString("default")).
+					KV2("password", linkedast.NewString("qwerty")).
+					KV2("dsn", linkedast.NewString("admin:qwerty")).
+					KV2(
+						"hosts",
+						testlinkedast.NewArrayBuilder().
+							Element(linkedast.NewString("example.com")).
+							Element(linkedast.NewInt(1)).
+							Build(),
+					)
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("missing_required", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+db ./db.atmc
+
+{
+	password: $DB_PASSWORD!
+	db...
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/db.atmc").
+					Content(`{
+	user: $DB_USER!
+	token: $TOKEN!
+}`)
+			}).
+			Env(func(eb *testos.EnvBuilder) {
+				eb.
+					Key("TOKEN").
+					Value("")
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrMissingEnv)
+		require.ErrorContains(t, err, "DB_USER at /home/user/db.atmc:2:7")
+		require.ErrorContains(t, err, "TOKEN at /home/user/db.atmc:3:8")
+		require.ErrorContains(t, err, "DB_PASSWORD at /home/user/config.atmc:5:11")
+	})
+
+	t.Run("missing_required_in_expression", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{a: $N:int! + 1}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrMissingEnv)
+		require.ErrorContains(t, err, "required environment variables are not set")
+		require.ErrorContains(t, err, "N at /home/user/config.atmc:1:4")
+	})
+
+	t.Run("default_in_expression", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	prod: $STAGE ?? "dev" == "prod"
+	debug: if $STAGE ?? "dev" == "prod" then false else true
+	workers: $WORKERS:int ?? 2 * 4
+	region: $REGION ?? $ZONE ?? "eu"
+}`)
+			}).
+			Env(func(eb *testos.EnvBuilder) {
+				eb.
+					Key("STAGE").
+					Value("prod")
+			}).
+			Env(func(eb *testos.EnvBuilder) {
+				eb.
+					Key("WORKERS").
+					Value("3")
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("prod", linkedast.NewBool(true)).
+					KV2("debug", linkedast.NewBool(false)).
+					KV2("workers", linkedast.NewInt(12)).
+					KV2("region", linkedast.NewString("eu"))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("required_marker_with_space", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{password: $DB_PASSWORD !}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.Error(t, err)
+	})
+
+	t.Run("typed", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		env := map[string]string{
+			"PORT":    "5432",
+			"RATIO":   "0.75",
+			"DEBUG":   "true",
+			"TIMEOUT": "1m30s",
+			"HOSTS":   `["a", "b"]`,
+			"LIMITS":  `{"cpu": 2, "memory": "1Gi", "burst": null}`,
+		}
+
+		osBuilder := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+{
+	port: $PORT:int
+	ratio: $RATIO:float
+	debug: $DEBUG:bool
+	timeout: $TIMEOUT:duration
+	hosts: $HOSTS:json
+	limits: $LIMITS:json!
+	name: $NAME:string
+	workers: $WORKERS:int ?? 4
+	url: "http://localhost:${$PORT:int}"
+}
+`)
+			})
+		for key, value := range env {
+			osBuilder.Env(func(eb *testos.EnvBuilder) {
+				eb.
+					Key(key).
+					Value(value)
+			})
+		}
+
+		app := test.NewApp(t, test.WithOS(osBuilder.Build()))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("port", linkedast.NewInt(5432)).
+					KV2("ratio", linkedast.NewFloat(0.75)).
+					KV2("debug", linkedast.NewBool(true)).
+					KV2("timeout", linkedast.NewDuration(90*time.Second)).
+					KV2(
+						"hosts",
+						testlinkedast.NewArrayBuilder().
+							Element(linkedast.NewString("a")).
+							Element(linkedast.NewString("b")).
+							Build(),
+					).
+					KV2(
+						"limits",
+						testlinkedast.NewObjectBuilder().
+							KV2("cpu", linkedast.NewInt(2)).
+							KV2("memory", linkedast.NewString("1Gi")).
+							KV2("burst", linkedast.NewNull()).
+							Build(),
+					).
+					KV2("name", linkedast.NewString("")).
+					KV2("workers", linkedast.NewInt(4)).
+					KV2("url", linkedast.NewString("http://localhost:5432"))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("typed_invalid_value", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	port: $PORT:int
+}`)
+			}).
+			Env(func(eb *testos.EnvBuilder) {
+				eb.
+					Key("PORT").
+					Value("http")
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrInvalidEnv)
+		require.ErrorContains(t, err, "cannot convert $PORT to int at /home/user/config.atmc:2:7")
+	})
+
+	t.Run("typed_non_finite_float", func(t *testing.T) {
+		t.Parallel()
+
+		for _, value := range []string{"inf", "+Inf", "-infinity", "NaN"} {
+			mainFilePath := "/home/user/config.atmc"
+
+			os := testos.NewOSBuilder().
+				File(func(fb *testos.FileBuilder) {
+					fb.
+						Path(mainFilePath).
+						Content(`{ratio: $RATIO:float}`)
+				}).
+				Env(func(eb *testos.EnvBuilder) {
+					eb.
+						Key("RATIO").
+						Value(value)
+				}).
+				Build()
+
+			app := test.NewApp(t, test.WithOS(os))
+
+			_, err := app.Processor().Process(mainFilePath)
+			require.ErrorIs(t, err, linker.ErrInvalidEnv)
+			require.ErrorContains(t, err, "cannot convert $RATIO to float at /home/user/config.atmc:1:8: unsupported float "+value)
+		}
+	})
+
+	t.Run("typed_unset_value", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{debug: $DEBUG:bool}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrInvalidEnv)
+		require.ErrorContains(t, err, "cannot convert $DEBUG to bool")
+	})
+
+	t.Run("unknown_type", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{port: $PORT:uint}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, parserast.ErrUnknownEnvType)
+		require.ErrorContains(t, err, "uint at 1:13")
+	})
+}
@@ -0,0 +1,241 @@
+package acceptance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/atmxlab/atmc/linker"
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_Expressions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("arithmetic_and_logic", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+base ./base.atmc
+
+{
+	max_conns: base.max_conns * 2
+	name: base.prefix + "-worker"
+	total: 1 + 2 * 3 - (4 - 2)
+	mod: 10 % 3
+	div: 7 / 2
+	ratio: 1 / 4.0
+	negative: -base.max_conns
+	timeout: base.timeout * 2 + 500ms
+	buffer: 2 * 64MiB
+	prod: $STAGE == "prod"
+	big: base.max_conns >= 10 && !(base.prefix != "app")
+	lazy: true || 1 / 0 == 1
+	mixed: 1 == 1.0
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/base.atmc").
+					Content(`{
+	max_conns: 10
+	prefix: "app"
+	timeout: 1s
+}`)
+			}).
+			Env(func(eb *testos.EnvBuilder) {
+				eb.
+					Key("STAGE").
+					Value("prod")
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("max_conns", linkedast.NewInt(20)).
+					KV2("name", linkedast.NewString("app-worker")).
+					KV2("total", linkedast.NewInt(5)).
+					KV2("mod", linkedast.NewInt(1)).
+					KV2("div", linkedast.NewInt(3)).
+					KV2("ratio", linkedast.NewFloat(0.25)).
+					KV2("negative", linkedast.NewInt(-10)).
+					KV2("timeout", linkedast.NewDuration(2500*time.Millisecond)).
+					KV2("buffer", linkedast.NewByteSize(128<<20)).
+					KV2("prod", linkedast.NewBool(true)).
+					KV2("big", linkedast.NewBool(true)).
+					KV2("lazy", linkedast.NewBool(true)).
+					KV2("mixed", linkedast.NewBool(true))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("equality_by_value", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+base ./base.atmc
+
+{
+	documented: base.documented == base.plain
+	with_strategy: base.with_strategy == base.plain
+	key_order: {port: 8080 host: "db"} == {host: "db" port: 8080}
+	arrays: [1 2] != [1 2 3]
+	nested: {hosts: ["a" "b"]} == {hosts: ["a" "b"]}
+	large_ints: 9007199254740993 == 9007199254740992
+	mixed: [1 2.5] == [1.0 2.5]
+	types: "1" == 1
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/base.atmc").
+					Content(`{
+	documented: {
+		/// Порты брокеров.
+		ports: [9092]
+	}
+	with_strategy: {
+		ports +: [9092]
+	}
+	plain: {
+		ports: [9092]
+	}
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("documented", linkedast.NewBool(true)).
+					KV2("with_strategy", linkedast.NewBool(true)).
+					KV2("key_order", linkedast.NewBool(true)).
+					KV2("arrays", linkedast.NewBool(true)).
+					KV2("nested", linkedast.NewBool(true)).
+					KV2("large_ints", linkedast.NewBool(false)).
+					KV2("mixed", linkedast.NewBool(true)).
+					KV2("types", linkedast.NewBool(false))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("invalid_operands", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	name: "worker-" + 1
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrInvalidOperands)
+		require.ErrorContains(t, err, "evaluate + at /home/user/config.atmc:2:17")
+		require.ErrorContains(t, err, "cannot apply + to string and int")
+	})
+
+	t.Run("division_by_zero", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	value: 10 / $WORKERS:int
+}`)
+			}).
+			Env(func(eb *testos.EnvBuilder) {
+				eb.
+					Key("WORKERS").
+					Value("0")
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrDivisionByZero)
+		require.ErrorContains(t, err, "evaluate / at /home/user/config.atmc:2:11")
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{value: 9223372036854775807 + 1}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrOverflow)
+	})
+
+	t.Run("logical_operand_not_bool", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{value: 1 && true}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrInvalidOperands)
+	})
+}
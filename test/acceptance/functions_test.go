@@ -0,0 +1,446 @@
+package acceptance
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/atmxlab/atmc/analyzer"
+	"github.com/atmxlab/atmc/linker"
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_Functions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("builtins", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+tls ./tls/tls.atmc
+
+{
+	upper: upper("prod")
+	lower: lower($STAGE)
+	trim: trim("  x  ")
+	trim_cutset: trim("--x--", "-")
+	replace: replace("a.b.c", ".", "/")
+	split: split("a,b", ",")
+	join: join(["a" 1 true], "-")
+	len: [len("привет") len([1 2 3]) len({a: 1})]
+	concat: concat([1], [2, 3])
+	concat_strings: concat("a", "b", "c")
+	keys: keys({b: 1, a: 2})
+	values: values({b: 1, a: 2})
+	merge: merge({a: {x: 1, y: 2}}, {a: {y: 3}, b: 4})
+	base64: base64("user:pass")
+	json: json({a: [1 2.5 "x"], b: null})
+	sha256: sha256("abc")
+	nested: upper(join(split("a-b", "-"), "_")) + "!"
+	ca: tls.ca
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/tls/tls.atmc").
+					Content(`{ca: trim(file("./ca.pem"))}`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/tls/ca.pem").
+					Content("-----BEGIN CERTIFICATE-----\n")
+			}).
+			Env(func(eb *testos.EnvBuilder) {
+				eb.
+					Key("STAGE").
+					Value("PROD")
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		strings := func(values ...string) linkedast.Array {
+			ab := testlinkedast.NewArrayBuilder()
+			for _, v := range values {
+				ab.Element(linkedast.NewString(v))
+			}
+
+			return ab.Build()
+		}
+
+		ints := func(values ...int64) linkedast.Array {
+			ab := testlinkedast.NewArrayBuilder()
+			for _, v := range values {
+				ab.Element(linkedast.NewInt(v))
+			}
+
+			return ab.Build()
+		}
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("upper", linkedast.NewString("PROD")).
+					KV2("lower", linkedast.NewString("prod")).
+					KV2("trim", linkedast.NewString("x")).
+					KV2("trim_cutset", linkedast.NewString("x")).
+					KV2("replace", linkedast.NewString("a/b/c")).
+					KV2("split", strings("a", "b")).
+					KV2("join", linkedast.NewString("a-1-true")).
+					KV2("len", ints(6, 3, 1)).
+					KV2("concat", ints(1, 2, 3)).
+					KV2("concat_strings", linkedast.NewString("abc")).
+					KV2("keys", strings("b", "a")).
+					KV2("values", ints(1, 2)).
+					KV2(
+						"merge",
+						testlinkedast.NewObjectBuilder().
+							KV2(
+								"a",
+								testlinkedast.NewObjectBuilder().
+									KV2("x", linkedast.NewInt(1)).
+									KV2("y", linkedast.NewInt(3)).
+									Build(),
+							).
+							KV2("b", linkedast.NewInt(4)).
+							Build(),
+					).
+					KV2("base64", linkedast.NewString("dXNlcjpwYXNz")).
+					KV2("json", linkedast.NewString(`{"a":[1,2.5,"x"],"b":null}`)).
+					KV2("sha256", linkedast.NewString("ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad")).
+					KV2("nested", linkedast.NewString("A_B!")).
+					KV2("ca", linkedast.NewString("-----BEGIN CERTIFICATE-----"))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("unknown_function", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	name: title("prod")
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, analyzer.ErrUnknownFunction)
+		require.ErrorContains(t, err, "title at 2:7")
+	})
+
+	t.Run("invalid_arguments", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	name: upper(1)
+	parts: split("a,b")
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrInvalidArguments)
+		require.ErrorContains(t, err, "call upper at /home/user/config.atmc:2:7: argument 1: expected string, got int")
+	})
+
+	t.Run("file_not_found", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{ca: file("./ca.pem")}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorContains(t, err, "call file at /home/user/config.atmc:1:5: read file /home/user/ca.pem")
+	})
+
+	t.Run("separated_paren_is_not_call", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+common ./common.atmc
+
+{
+	values: [common.port (1 + 2)]
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`{port: 80}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.KV2(
+					"values",
+					testlinkedast.NewArrayBuilder().
+						Element(linkedast.NewInt(80)).
+						Element(linkedast.NewInt(3)).
+						Build(),
+				)
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("user_functions", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	endpoint: region_endpoint("eu")
+	sum: sum(1, 2, 3)
+	scaled: scale(1.5, 2)
+	timeout: double(1m30s)
+	ports: ports({http: 80, https: 443})
+	labels: labels(["a" "b"])
+	first: first([{name: "x"}, 2])
+	optional: optional(null)
+	size: bytes(1KiB)
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(
+			t,
+			test.WithOS(os),
+			test.WithFunction(t, "region_endpoint", func(region string) string {
+				return "https://" + region + ".example.com"
+			}),
+			test.WithFunction(t, "sum", func(values ...int) int {
+				total := 0
+				for _, v := range values {
+					total += v
+				}
+
+				return total
+			}),
+			test.WithFunction(t, "scale", func(f float64, n int8) float32 {
+				return float32(f * float64(n))
+			}),
+			test.WithFunction(t, "double", func(d time.Duration) time.Duration {
+				return 2 * d
+			}),
+			test.WithFunction(t, "ports", func(m map[string]uint16) []uint16 {
+				return []uint16{m["http"], m["https"]}
+			}),
+			test.WithFunction(t, "labels", func(values []string) map[string]bool {
+				labels := make(map[string]bool, len(values))
+				for _, v := range values {
+					labels[v] = true
+				}
+
+				return labels
+			}),
+			test.WithFunction(t, "first", func(values []any) any {
+				return values[0]
+			}),
+			test.WithFunction(t, "optional", func(s *string) *string {
+				return s
+			}),
+			test.WithFunction(t, "bytes", func(n uint64) uint64 {
+				return n
+			}),
+		)
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("endpoint", linkedast.NewString("https://eu.example.com")).
+					KV2("sum", linkedast.NewInt(6)).
+					KV2("scaled", linkedast.NewFloat(3)).
+					KV2("timeout", linkedast.NewDuration(3*time.Minute)).
+					KV2(
+						"ports",
+						testlinkedast.NewArrayBuilder().
+							Element(linkedast.NewInt(80)).
+							Element(linkedast.NewInt(443)).
+							Build(),
+					).
+					KV2(
+						"labels",
+						testlinkedast.NewObjectBuilder().
+							KV2("a", linkedast.NewBool(true)).
+							KV2("b", linkedast.NewBool(true)).
+							Build(),
+					).
+					KV2(
+						"first",
+						testlinkedast.NewObjectBuilder().
+							KV2("name", linkedast.NewString("x")).
+							Build(),
+					).
+					KV2("optional", linkedast.NewNull()).
+					KV2("size", linkedast.NewInt(1024))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("user_function_errors", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			name     string
+			content  string
+			expected error
+			message  string
+		}{
+			{
+				name:     "returned_error",
+				content:  `{password: secret("db/password")}`,
+				expected: errSecretNotFound,
+				message:  "call secret at /home/user/config.atmc:1:11: secret not found",
+			},
+			{
+				name:     "invalid_argument",
+				content:  `{port: port("80")}`,
+				expected: linker.ErrInvalidArguments,
+				message:  "call port at /home/user/config.atmc:1:7: argument 1: cannot convert string to uint16",
+			},
+			{
+				name:     "argument_overflow",
+				content:  `{port: port(70000)}`,
+				expected: linker.ErrOverflow,
+				message:  "argument 1: 70000 overflows uint16",
+			},
+			{
+				name:     "arguments_count",
+				content:  `{port: port(1 2)}`,
+				expected: linker.ErrInvalidArguments,
+				message:  "expected at most 1 arguments, got 2",
+			},
+			{
+				name:     "panic",
+				content:  `{lookup: lookup([] 3)}`,
+				expected: linker.ErrFunctionPanic,
+				message:  "call lookup at /home/user/config.atmc:1:9",
+			},
+			{
+				name:     "float32_overflow",
+				content:  `{ratio: ratio(1e39)}`,
+				expected: linker.ErrOverflow,
+				message:  "argument 1: 1e+39 overflows float32",
+			},
+			{
+				name:     "non_finite_result",
+				content:  `{ratio: ratio(0) / 2}`,
+				expected: linker.ErrInvalidResult,
+				message:  "call ratio at /home/user/config.atmc:1:8: result: unsupported float +Inf",
+			},
+			{
+				name:     "nan_in_result",
+				content:  `{stats: stats()}`,
+				expected: linker.ErrInvalidResult,
+				message:  "result: key p99: unsupported float NaN",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				mainFilePath := "/home/user/config.atmc"
+
+				os := testos.NewOSBuilder().
+					File(func(fb *testos.FileBuilder) {
+						fb.
+							Path(mainFilePath).
+							Content(tc.content)
+					}).
+					Build()
+
+				app := test.NewApp(
+					t,
+					test.WithOS(os),
+					test.WithFunction(t, "secret", func(string) (string, error) {
+						return "", errSecretNotFound
+					}),
+					test.WithFunction(t, "port", func(p uint16) uint16 {
+						return p
+					}),
+					test.WithFunction(t, "lookup", func(values []string, i int) string {
+						return values[i]
+					}),
+					test.WithFunction(t, "ratio", func(f float32) float32 {
+						return 1 / f
+					}),
+					test.WithFunction(t, "stats", func() map[string]any {
+						return map[string]any{"p50": 1.5, "p99": math.NaN()}
+					}),
+				)
+
+				_, err := app.Processor().Process(mainFilePath)
+				require.ErrorIs(t, err, tc.expected)
+				require.ErrorContains(t, err, tc.message)
+			})
+		}
+	})
+}
+
+var errSecretNotFound = errors.New("secret not found")
@@ -0,0 +1,312 @@
+package acceptance
+
+import (
+	"testing"
+
+	"github.com/atmxlab/atmc/linker"
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/parser"
+	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_GlobImport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("glob_and_directory", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+services ./services/*.atmc
+limits ./limits/
+
+{
+	services: services
+	billing_port: services.billing.port
+	limits...
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/services/orders.atmc").
+					Content(`
+common ./../common.atmc
+
+{
+	port: common.base_port + 2
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/services/billing.atmc").
+					Content(`
+common ./../common.atmc
+
+{
+	port: common.base_port + 1
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/services/readme.md").
+					Content(`not a config`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`{base_port: 8080}`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/limits/rps.json").
+					Content(`{"max": 100}`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/limits/connections.atmc").
+					Content(`{max: 10}`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/limits/notes.txt").
+					Content(`ignored`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2(
+						"services",
+						testlinkedast.NewObjectBuilder().
+							KV2("billing", testlinkedast.NewObjectBuilder().KV2("port", linkedast.NewInt(8081)).Build()).
+							KV2("orders", testlinkedast.NewObjectBuilder().KV2("port", linkedast.NewInt(8082)).Build()).
+							Build(),
+					).
+					KV2("billing_port", linkedast.NewInt(8081)).
+					KV2("connections", testlinkedast.NewObjectBuilder().KV2("max", linkedast.NewInt(10)).Build()).
+					KV2("rps", testlinkedast.NewObjectBuilder().KV2("max", linkedast.NewInt(100)).Build())
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("empty_glob", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+services ./services/*.atmc
+
+{
+	services: services
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, errors.ErrNotFound)
+		require.ErrorContains(t, err, "no files match import ./services/*.atmc")
+	})
+
+	t.Run("empty_optional_glob", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+services ?./services/*.atmc
+limits ?./limits/
+
+{
+	services: services
+	limits: limits
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("services", linkedast.NewObject([]linkedast.KV{})).
+					KV2("limits", linkedast.NewObject([]linkedast.KV{}))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("dotfiles", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+conf ./conf/
+env ./conf/.env
+
+{
+	conf: conf
+	token: env.TOKEN
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/conf/.env").
+					Content(`TOKEN=abc`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/conf/app.env").
+					Content(`PORT=80`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2(
+						"conf",
+						testlinkedast.NewObjectBuilder().
+							KV2("app", testlinkedast.NewObjectBuilder().KV2("PORT", linkedast.NewString("80")).Build()).
+							Build(),
+					).
+					KV2("token", linkedast.NewString("abc"))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+configs ./*.atmc
+
+{
+	configs: configs
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorContains(t, err, "import cycle detected")
+	})
+
+	t.Run("duplicate_name", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+limits ./limits/
+
+{
+	limits: limits
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/limits/rps.atmc").
+					Content(`{max: 10}`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/limits/rps.json").
+					Content(`{"max": 100}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrDuplicateImportName)
+		require.ErrorContains(t, err, "rps: /home/user/limits/rps.atmc and /home/user/limits/rps.json")
+	})
+
+	t.Run("unsupported_pattern", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`services ./services/**/*.atmc
+
+{
+	services: services
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, parser.ErrUnexpectedToken)
+		require.ErrorContains(t, err, "unsupported import pattern ./services/**/*.atmc at 1:")
+	})
+}
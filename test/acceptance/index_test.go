@@ -0,0 +1,173 @@
+package acceptance
+
+import (
+	"testing"
+
+	"github.com/atmxlab/atmc/analyzer"
+	"github.com/atmxlab/atmc/linker"
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_IndexAccess(t *testing.T) {
+	t.Parallel()
+
+	t.Run("index_segments", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+infra ./infra.atmc
+levels = ["warn" "error"]
+
+{
+	broker: infra.brokers.hosts[0]
+	last_endpoint: infra.clusters[-1].endpoint
+	level: levels[1]
+	replicas: [
+		{port: 8080}
+		{port: root.replicas[0].port + 1}
+	]
+	first_port: root.replicas[0].port
+	url: "http://${infra.brokers.hosts[-1]}"
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/infra.atmc").
+					Content(`
+{
+	brokers: {
+		hosts: ["kafka-1:9092" "kafka-2:9092"]
+	}
+	clusters: [
+		{endpoint: "eu.internal"}
+		{endpoint: "us.internal"}
+	]
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("broker", linkedast.NewString("kafka-1:9092")).
+					KV2("last_endpoint", linkedast.NewString("us.internal")).
+					KV2("level", linkedast.NewString("error")).
+					KV2(
+						"replicas",
+						testlinkedast.NewArrayBuilder().
+							Element(testlinkedast.NewObjectBuilder().KV2("port", linkedast.NewInt(8080)).Build()).
+							Element(testlinkedast.NewObjectBuilder().KV2("port", linkedast.NewInt(8081)).Build()).
+							Build(),
+					).
+					KV2("first_port", linkedast.NewInt(8080)).
+					KV2("url", linkedast.NewString("http://kafka-2:9092"))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("out_of_range", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+infra ./infra.atmc
+
+{
+	broker: infra.brokers.hosts[2]
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/infra.atmc").
+					Content(`{brokers: {hosts: ["kafka-1:9092" "kafka-2:9092"]}}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linkedast.ErrIndexOutOfRange)
+		require.ErrorContains(t, err, "find infra.brokers.hosts[2]")
+		require.ErrorContains(t, err, "brokers.hosts[2]: index 2 out of range for array of length 2")
+	})
+
+	t.Run("index_of_not_array", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+infra ./infra.atmc
+
+{
+	broker: infra.brokers[0].host
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/infra.atmc").
+					Content(`{brokers: {host: "kafka-1:9092"}}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrNotFoundVariable)
+		require.ErrorContains(t, err, "find infra.brokers[0].host")
+		require.ErrorContains(t, err, "brokers is not an array")
+	})
+
+	t.Run("out_of_range_in_document", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	hosts: ["a" "b"]
+	first: root.hosts[-3]
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, analyzer.ErrIndexOutOfRange)
+		require.ErrorContains(t, err, "root.hosts[-3]: index -3 out of range for array of length 2 at 3:18")
+	})
+}
@@ -0,0 +1,164 @@
+package acceptance
+
+import (
+	"testing"
+
+	"github.com/atmxlab/atmc/analyzer"
+	"github.com/atmxlab/atmc/linker"
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_Interpolation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("vars_and_envs_in_strings", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+db ./db.atmc
+
+{
+	dsn: "postgres://${db.user}@${db.host}:${$PG_PORT}/app"
+	pool: ["${db.pool.size}" "${db.pool.timeout}" "${db."x-ssl"}"]
+	escaped: "\${db.user}"
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/db.atmc").
+					Content(`{
+	user: "admin"
+	host: "localhost"
+	pool: {
+		size: 10
+		timeout: 1m30s
+	}
+	"x-ssl": true
+}`)
+			}).
+			Env(func(eb *testos.EnvBuilder) {
+				eb.
+					Key("PG_PORT").
+					Value("5432")
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("dsn", linkedast.NewString("postgres://admin@localhost:5432/app")).
+					KV2(
+						"pool",
+						testlinkedast.NewArrayBuilder().
+							Element(linkedast.NewString("10")).
+							Element(linkedast.NewString("1m30s")).
+							Element(linkedast.NewString("true")).
+							Build(),
+					).
+					KV2("escaped", linkedast.NewString("${db.user}"))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("import_used_only_in_interpolation", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+db ./db.atmc
+unused ./db.atmc
+
+{
+	dsn: "${db.host}"
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/db.atmc").
+					Content(`{host: "localhost"}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, analyzer.ErrUnusedVariable)
+		require.ErrorContains(t, err, "unused")
+		require.NotContains(t, err.Error(), "db")
+	})
+
+	t.Run("undefined_variable_in_interpolation", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{dsn: "${db.host}"}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, analyzer.ErrUndefinedVariable)
+		require.ErrorContains(t, err, "undefined variable: db")
+	})
+
+	t.Run("object_in_interpolation", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+db ./db.atmc
+
+{
+	dsn: "postgres://${db.pool}"
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/db.atmc").
+					Content(`{pool: {size: 10}}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrUnexpectedNodeType)
+		require.ErrorContains(t, err, "interpolate db.pool at 5:20")
+	})
+}
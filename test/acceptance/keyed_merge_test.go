@@ -0,0 +1,134 @@
+package acceptance
+
+import (
+	"testing"
+
+	"github.com/atmxlab/atmc/linker"
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/parser"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_KeyedMerge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("merge_by_name", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+common ./common.atmc
+
+{
+	common...
+	services @merge(name): [
+		{name: "api" replicas: 5}
+		{name: "worker" replicas: 1}
+	]
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`
+{
+	services: [
+		{name: "api" replicas: 2 port: 8080}
+		{name: "cron" replicas: 1}
+	]
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.KV2(
+					"services",
+					testlinkedast.NewArrayBuilder().
+						Element(
+							testlinkedast.NewObjectBuilder().
+								KV2("name", linkedast.NewString("api")).
+								KV2("replicas", linkedast.NewInt(5)).
+								KV2("port", linkedast.NewInt(8080)).
+								Build(),
+						).
+						Element(
+							testlinkedast.NewObjectBuilder().
+								KV2("name", linkedast.NewString("cron")).
+								KV2("replicas", linkedast.NewInt(1)).
+								Build(),
+						).
+						Element(
+							testlinkedast.NewObjectBuilder().
+								KV2("name", linkedast.NewString("worker")).
+								KV2("replicas", linkedast.NewInt(1)).
+								Build(),
+						).
+						Build(),
+				)
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("element_without_key", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	services: [{name: "api"}]
+	services @merge(name): [{replicas: 5}]
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrInvalidMerge)
+		require.ErrorContains(t, err, "merge services at /home/user/config.atmc:3:1: element 0: expected object with scalar name, got object")
+	})
+
+	t.Run("unknown_annotation", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	services @patch(name): []
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, parser.ErrUnexpectedToken)
+		require.ErrorContains(t, err, "unknown annotation @patch at 2:11")
+	})
+}
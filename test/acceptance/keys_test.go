@@ -0,0 +1,74 @@
+package acceptance
+
+import (
+	"testing"
+
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_QuotedKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("quoted_keys_in_objects_and_var_paths", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+labels ./labels.atmc
+
+{
+	headers: {
+		"x-request-id": "abc"
+		"X-Forwarded-For": labels."app.kubernetes.io/name"
+	}
+	ports: {
+		"8080": "http"
+	}
+	labels...
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/labels.atmc").
+					Content(`{"app.kubernetes.io/name": "api"}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2(
+						"headers",
+						testlinkedast.NewObjectBuilder().
+							KV2("x-request-id", linkedast.NewString("abc")).
+							KV2("X-Forwarded-For", linkedast.NewString("api")).
+							Build(),
+					).
+					KV2(
+						"ports",
+						testlinkedast.NewObjectBuilder().
+							KV2("8080", linkedast.NewString("http")).
+							Build(),
+					).
+					KV2("app.kubernetes.io/name", linkedast.NewString("api"))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+}
@@ -0,0 +1,221 @@
+package acceptance
+
+import (
+	"testing"
+
+	"github.com/atmxlab/atmc/linker"
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_MergeStrategies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("append_prepend_replace", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+common ./common.atmc
+
+{
+	common...
+	logging: {
+		levels +: ["debug"]
+	}
+	brokers ^: ["kafka-0:9092"]
+	tracing =: {
+		enabled: false
+	}
+	hosts =: ["replica"]
+	ports +: [8080]
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`
+{
+	logging: {
+		levels: ["warn" "error"]
+		format: "json"
+	}
+	brokers: ["kafka-1:9092" "kafka-2:9092"]
+	tracing: {
+		enabled: true
+		endpoint: "http://jaeger:14268"
+	}
+	hosts: ["primary"]
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2(
+						"logging",
+						testlinkedast.NewObjectBuilder().
+							KV2(
+								"levels",
+								testlinkedast.NewArrayBuilder().
+									Element(linkedast.NewString("warn")).
+									Element(linkedast.NewString("error")).
+									Element(linkedast.NewString("debug")).
+									Build(),
+							).
+							KV2("format", linkedast.NewString("json")).
+							Build(),
+					).
+					KV2(
+						"brokers",
+						testlinkedast.NewArrayBuilder().
+							Element(linkedast.NewString("kafka-0:9092")).
+							Element(linkedast.NewString("kafka-1:9092")).
+							Element(linkedast.NewString("kafka-2:9092")).
+							Build(),
+					).
+					KV2(
+						"tracing",
+						testlinkedast.NewObjectBuilder().
+							KV2("enabled", linkedast.NewBool(false)).
+							Build(),
+					).
+					KV2(
+						"hosts",
+						testlinkedast.NewArrayBuilder().
+							Element(linkedast.NewString("replica")).
+							Build(),
+					).
+					KV2(
+						"ports",
+						testlinkedast.NewArrayBuilder().
+							Element(linkedast.NewInt(8080)).
+							Build(),
+					)
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("strategies_from_spread_import", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+common ./common.atmc
+overlay ./overlay.atmc
+
+{
+	common...
+	overlay...
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`
+{
+	log: {
+		level: ["info"]
+	}
+	brokers: ["kafka-1:9092"]
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/overlay.atmc").
+					Content(`
+{
+	log: {
+		level +: ["debug"]
+	}
+	brokers ^: ["kafka-0:9092"]
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2(
+						"log",
+						testlinkedast.NewObjectBuilder().
+							KV2(
+								"level",
+								testlinkedast.NewArrayBuilder().
+									Element(linkedast.NewString("info")).
+									Element(linkedast.NewString("debug")).
+									Build(),
+							).
+							Build(),
+					).
+					KV2(
+						"brokers",
+						testlinkedast.NewArrayBuilder().
+							Element(linkedast.NewString("kafka-0:9092")).
+							Element(linkedast.NewString("kafka-1:9092")).
+							Build(),
+					)
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("append_to_not_array", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	logging: {
+		level: "info"
+	}
+	logging: {
+		level +: ["debug"]
+	}
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrInvalidMerge)
+		require.ErrorContains(t, err, "merge logging at /home/user/config.atmc:5:1: key level: cannot apply +: to string and array")
+	})
+}
@@ -0,0 +1,386 @@
+package acceptance
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/processor"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_ForeignModules(t *testing.T) {
+	t.Parallel()
+
+	t.Run("json_yaml_toml_env", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+pkg ./package.json
+vals ./values.yaml
+db ./db.toml
+env ./.env
+
+{
+	vals...
+	name: pkg.name
+	version: "v${pkg.version}"
+	db: {
+		db.database...
+		replicas: db.replica[-1].host
+	}
+	token: env.API_TOKEN
+	greeting: env.GREETING
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/package.json").
+					Content(`{"name": "billing", "version": "1.2.0", "private": true}`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/values.yaml").
+					Content(`
+defaults: &defaults
+  retries: 3
+  timeout: 1.5
+service:
+  <<: *defaults
+  retries: 5
+  tags: [api, "public"]
+  owner: null
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/db.toml").
+					Content(`
+# база данных
+[database]
+host = "db.internal"
+port = 5_432
+options = { ssl = true, "pool.size" = 10 }
+
+[[replica]]
+host = 'replica-1'
+
+[[replica]]
+host = 'replica-2'
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/.env").
+					Content(`
+# секреты
+export API_TOKEN=abc123 # комментарий
+GREETING="hello\nworld"
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		defaults := testlinkedast.NewObjectBuilder().
+			KV2("retries", linkedast.NewInt(3)).
+			KV2("timeout", linkedast.NewFloat(1.5)).
+			Build()
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("defaults", defaults).
+					KV2(
+						"service",
+						testlinkedast.NewObjectBuilder().
+							KV2("retries", linkedast.NewInt(5)).
+							KV2("timeout", linkedast.NewFloat(1.5)).
+							KV2(
+								"tags",
+								testlinkedast.NewArrayBuilder().
+									Element(linkedast.NewString("api")).
+									Element(linkedast.NewString("public")).
+									Build(),
+							).
+							KV2("owner", linkedast.NewNull()).
+							Build(),
+					).
+					KV2("name", linkedast.NewString("billing")).
+					KV2("version", linkedast.NewString("v1.2.0")).
+					KV2(
+						"db",
+						testlinkedast.NewObjectBuilder().
+							KV2("host", linkedast.NewString("db.internal")).
+							KV2("port", linkedast.NewInt(5432)).
+							KV2(
+								"options",
+								testlinkedast.NewObjectBuilder().
+									KV2("ssl", linkedast.NewBool(true)).
+									KV2("pool.size", linkedast.NewInt(10)).
+									Build(),
+							).
+							KV2("replicas", linkedast.NewString("replica-2")).
+							Build(),
+					).
+					KV2("token", linkedast.NewString("abc123")).
+					KV2("greeting", linkedast.NewString("hello\nworld"))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("toml_order_and_dates", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/release.toml"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+zeta = 1
+alpha.beta = 0x1F
+released = 1979-05-27 07:32:00Z
+day = 1979-05-27
+at = 07:32:00
+
+[server]
+port = 8080
+host = "localhost"
+
+[[targets]]
+name = "linux"
+arch = "amd64"
+
+[[targets]]
+arch = "arm64"
+name = "darwin"
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("zeta", linkedast.NewInt(1)).
+					KV2("alpha", testlinkedast.NewObjectBuilder().KV2("beta", linkedast.NewInt(31)).Build()).
+					KV2("released", linkedast.NewString("1979-05-27T07:32:00Z")).
+					KV2("day", linkedast.NewString("1979-05-27")).
+					KV2("at", linkedast.NewString("07:32:00")).
+					KV2(
+						"server",
+						testlinkedast.NewObjectBuilder().
+							KV2("port", linkedast.NewInt(8080)).
+							KV2("host", linkedast.NewString("localhost")).
+							Build(),
+					).
+					KV2(
+						"targets",
+						testlinkedast.NewArrayBuilder().
+							Element(
+								testlinkedast.NewObjectBuilder().
+									KV2("name", linkedast.NewString("linux")).
+									KV2("arch", linkedast.NewString("amd64")).
+									Build(),
+							).
+							Element(
+								testlinkedast.NewObjectBuilder().
+									KV2("name", linkedast.NewString("darwin")).
+									KV2("arch", linkedast.NewString("arm64")).
+									Build(),
+							).
+							Build(),
+					)
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("main_file_in_other_format", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/levels.yml"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content("- warn\n- error\n")
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Root(
+				testlinkedast.NewArrayBuilder().
+					Element(linkedast.NewString("warn")).
+					Element(linkedast.NewString("error")).
+					Build(),
+			).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("errors_with_position", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			name     string
+			path     string
+			content  string
+			expected string
+		}{
+			{
+				name:     "json",
+				path:     "/home/user/package.json",
+				content:  "{\n  \"name\": \"billing\",\n  \"version\" 1\n}",
+				expected: "json: invalid character '1' after object key at 3:13",
+			},
+			{
+				name:     "yaml",
+				path:     "/home/user/values.yaml",
+				content:  "a: 1\nb: c: d\n",
+				expected: "yaml: line 2: mapping values are not allowed in this context",
+			},
+			{
+				name:     "yaml_inf",
+				path:     "/home/user/values.yaml",
+				content:  "limits:\n  max: .inf\n",
+				expected: "yaml: unsupported float .inf at 2:8",
+			},
+			{
+				name:     "yaml_nested_aliases",
+				path:     "/home/user/values.yaml",
+				content:  nestedYAMLAliases(9, 10),
+				expected: "yaml: excessive aliasing at",
+			},
+			{
+				name:     "toml",
+				path:     "/home/user/db.toml",
+				content:  "[database]\nhost = \"db\"\nhost = \"other\"\n",
+				expected: "toml: key host is already defined at 3:1",
+			},
+			{
+				name:     "toml_value",
+				path:     "/home/user/db.toml",
+				content:  "port = 0755\n",
+				expected: "toml: expected newline but got U+0037 '7' at 1:9",
+			},
+			{
+				name:     "toml_uppercase_prefix",
+				path:     "/home/user/db.toml",
+				content:  "mode = 0X1F\n",
+				expected: "toml: expected newline but got U+0058 'X' at 1:9",
+			},
+			{
+				name:     "toml_dotted_key_table",
+				path:     "/home/user/db.toml",
+				content:  "[a]\nb.c = 1\n\n[a.b]\nd = 2\n",
+				expected: "toml: table b already exists at 4:2",
+			},
+			{
+				name:     "toml_inf",
+				path:     "/home/user/db.toml",
+				content:  "[limits]\nmax = inf\n",
+				expected: "toml: unsupported float inf at 2:7",
+			},
+			{
+				name:     "toml_nan",
+				path:     "/home/user/db.toml",
+				content:  "ratios = [1.5, -nan]\n",
+				expected: "toml: unsupported float -nan at 1:16",
+			},
+			{
+				name:     "env",
+				path:     "/home/user/.env",
+				content:  "A=1\nB=\"unterminated\n",
+				expected: "dotenv: unterminated quoted value at 2:3",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				mainFilePath := "/home/user/config.atmc"
+
+				os := testos.NewOSBuilder().
+					File(func(fb *testos.FileBuilder) {
+						fb.
+							Path(mainFilePath).
+							Content(`
+module ` + "." + tc.path[len("/home/user"):] + `
+
+{
+	module...
+}
+`)
+					}).
+					File(func(fb *testos.FileBuilder) {
+						fb.
+							Path(tc.path).
+							Content(tc.content)
+					}).
+					Build()
+
+				app := test.NewApp(t, test.WithOS(os))
+
+				_, err := app.Processor().Process(mainFilePath)
+				require.ErrorIs(t, err, processor.ErrInvalidModule)
+				require.ErrorContains(t, err, tc.expected)
+				require.ErrorContains(t, err, "file: "+tc.path)
+			})
+		}
+	})
+}
+
+// nestedYAMLAliases документ, в котором каждый уровень ссылается width раз на предыдущий: раскрывается в width^levels узлов.
+func nestedYAMLAliases(levels, width int) string {
+	var b strings.Builder
+
+	b.WriteString("l0: &l0 [x]\n")
+
+	for i := 1; i <= levels; i++ {
+		fmt.Fprintf(&b, "l%d: &l%d [", i, i)
+
+		for j := 0; j < width; j++ {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+
+			fmt.Fprintf(&b, "*l%d", i-1)
+		}
+
+		b.WriteString("]\n")
+	}
+
+	return b.String()
+}
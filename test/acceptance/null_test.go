@@ -0,0 +1,119 @@
+package acceptance
+
+import (
+	"testing"
+
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_Null(t *testing.T) {
+	t.Parallel()
+
+	t.Run("null_overrides_inherited_values", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+common ./common.atmc
+
+{
+	common...
+	tracing: null
+	proxy: {
+		host: "proxy.internal"
+	}
+	levels: [null, "warn"]
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`{
+	tracing: {
+		enabled: true
+	}
+	proxy: null
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("tracing", linkedast.NewNull()).
+					KV2(
+						"proxy",
+						testlinkedast.NewObjectBuilder().
+							KV2("host", linkedast.NewString("proxy.internal")).
+							Build(),
+					).
+					KV2(
+						"levels",
+						testlinkedast.NewArrayBuilder().
+							Element(linkedast.NewNull()).
+							Element(linkedast.NewString("warn")).
+							Build(),
+					)
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("keyword_keys", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+flags = {null: "none" true: 1 if: "a" then: "b" else: "c"}
+
+{
+	flags...
+	none: flags.null
+	cond: if flags.true == 1 then flags.then else flags.else
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("null", linkedast.NewString("none")).
+					KV2("true", linkedast.NewInt(1)).
+					KV2("if", linkedast.NewString("a")).
+					KV2("then", linkedast.NewString("b")).
+					KV2("else", linkedast.NewString("c")).
+					KV2("none", linkedast.NewString("none")).
+					KV2("cond", linkedast.NewString("b"))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+}
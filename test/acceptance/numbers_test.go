@@ -0,0 +1,111 @@
+package acceptance
+
+import (
+	"testing"
+
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	parserast "github.com/atmxlab/atmc/parser/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_Numbers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("numeric_literal_forms", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+{
+	mode: 0o755
+	mask: 0xFF
+	flags: 0b1010
+	max_bytes: 1_000_000
+	epsilon: 1e-9
+	ratio: .5
+	values: [-0x10 +.25]
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("mode", linkedast.NewInt(0o755)).
+					KV2("mask", linkedast.NewInt(0xFF)).
+					KV2("flags", linkedast.NewInt(0b1010)).
+					KV2("max_bytes", linkedast.NewInt(1_000_000)).
+					KV2("epsilon", linkedast.NewFloat(1e-9)).
+					KV2("ratio", linkedast.NewFloat(.5)).
+					KV2(
+						"values",
+						testlinkedast.NewArrayBuilder().
+							Element(linkedast.NewInt(-0x10)).
+							Element(linkedast.NewFloat(.25)).
+							Build(),
+					)
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("int_overflow", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	size: 0xFFFF_FFFF_FFFF_FFFF
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, parserast.ErrNumberOverflow)
+		require.ErrorContains(t, err, "at 2:7")
+	})
+
+	t.Run("decimal_with_leading_zero", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	mode: 0755
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, parserast.ErrInvalidNumber)
+		require.ErrorContains(t, err, "leading zero in decimal 0755, use 0o prefix for octal")
+		require.ErrorContains(t, err, "at 2:7")
+	})
+}
@@ -0,0 +1,139 @@
+package acceptance
+
+import (
+	"testing"
+
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/pkg/errors"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_OptionalImport(t *testing.T) {
+	t.Parallel()
+
+	mainContent := `
+local ?./local.atmc
+secrets ?./secrets.json
+
+{
+	debug: false
+	port: 8080
+	local...
+	overrides: local
+	secrets: secrets
+}
+`
+
+	t.Run("existing_file", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(mainContent)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/local.atmc").
+					Content(`{debug: true, verbose: true}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		local := testlinkedast.NewObjectBuilder().
+			KV2("debug", linkedast.NewBool(true)).
+			KV2("verbose", linkedast.NewBool(true)).
+			Build()
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("debug", linkedast.NewBool(true)).
+					KV2("port", linkedast.NewInt(8080)).
+					KV2("verbose", linkedast.NewBool(true)).
+					KV2("overrides", local).
+					KV2("secrets", linkedast.NewObject([]linkedast.KV{}))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("missing_file", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(mainContent)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("debug", linkedast.NewBool(false)).
+					KV2("port", linkedast.NewInt(8080)).
+					KV2("overrides", linkedast.NewObject([]linkedast.KV{})).
+					KV2("secrets", linkedast.NewObject([]linkedast.KV{}))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("missing_import_of_optional_file", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+local ?./local.atmc
+
+{
+	local...
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/local.atmc").
+					Content(`
+common ./common.atmc
+
+{
+	common...
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, errors.ErrNotFound)
+		require.ErrorContains(t, err, "read file content")
+	})
+}
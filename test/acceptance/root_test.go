@@ -0,0 +1,186 @@
+package acceptance
+
+import (
+	"testing"
+
+	"github.com/atmxlab/atmc/linker"
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_NonObjectRoot(t *testing.T) {
+	t.Parallel()
+
+	t.Run("array_root", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+levels ./levels.atmc
+port ./port.atmc
+
+{
+	levels: levels
+	first_level: levels[0]
+	port: port + 1
+}
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/levels.atmc").
+					Content(`
+common ./common.atmc
+fatal = "fatal"
+
+[common.level "error" fatal]
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/common.atmc").
+					Content(`{level: "warn"}`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/port.atmc").
+					Content(`8080`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2(
+						"levels",
+						testlinkedast.NewArrayBuilder().
+							Element(linkedast.NewString("warn")).
+							Element(linkedast.NewString("error")).
+							Element(linkedast.NewString("fatal")).
+							Build(),
+					).
+					KV2("first_level", linkedast.NewString("warn")).
+					KV2("port", linkedast.NewInt(8081))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+
+		a, err = app.Processor().Process("/home/user/levels.atmc")
+		require.NoError(t, err)
+
+		expectedAst = testlinkedast.NewBuilder().
+			Root(
+				testlinkedast.NewArrayBuilder().
+					Element(linkedast.NewString("warn")).
+					Element(linkedast.NewString("error")).
+					Element(linkedast.NewString("fatal")).
+					Build(),
+			).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("root_reference_in_array_root", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`[1 root.x]`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrNotFoundVariable)
+		require.ErrorContains(t, err, "root.x: document root is not an object at /home/user/config.atmc:1:3")
+	})
+
+	t.Run("import_reference_root", func(t *testing.T) {
+		t.Parallel()
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/config.atmc").
+					Content(`
+b ./arr.json
+
+b
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/sum.atmc").
+					Content(`
+b ./port.atmc
+
+1 + b
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/cond.atmc").
+					Content(`
+b ./port.atmc
+
+if b > 100 then 0 else b
+`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/arr.json").
+					Content(`[1, 2]`)
+			}).
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path("/home/user/port.atmc").
+					Content(`80`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process("/home/user/config.atmc")
+		require.NoError(t, err)
+		require.Equal(
+			t,
+			testlinkedast.NewBuilder().
+				Root(
+					testlinkedast.NewArrayBuilder().
+						Element(linkedast.NewInt(1)).
+						Element(linkedast.NewInt(2)).
+						Build(),
+				).
+				Build(),
+			a,
+		)
+
+		a, err = app.Processor().Process("/home/user/sum.atmc")
+		require.NoError(t, err)
+		require.Equal(t, testlinkedast.NewBuilder().Root(linkedast.NewInt(81)).Build(), a)
+
+		a, err = app.Processor().Process("/home/user/cond.atmc")
+		require.NoError(t, err)
+		require.Equal(t, testlinkedast.NewBuilder().Root(linkedast.NewInt(80)).Build(), a)
+	})
+}
@@ -0,0 +1,195 @@
+package acceptance
+
+import (
+	"testing"
+
+	"github.com/atmxlab/atmc/analyzer"
+	"github.com/atmxlab/atmc/linker"
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_SelfReferences(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sibling_and_root_keys", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+base = {
+	name: "base"
+	title: upper(self.name)
+}
+
+{
+	server: {
+		public_url: "http://${self.host}:${self.port}"
+		host: "localhost"
+		port: root.defaults.port + 1
+	}
+	defaults: {
+		port: 8080
+	}
+	defaults: {
+		timeout: 5s
+	}
+	timeout: root.defaults.timeout
+	replicas: [
+		{name: "a" url: "http://${self.name}:${root.server.port}"}
+	]
+	base: base
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2(
+						"server",
+						testlinkedast.NewObjectBuilder().
+							KV2("public_url", linkedast.NewString("http://localhost:8081")).
+							KV2("host", linkedast.NewString("localhost")).
+							KV2("port", linkedast.NewInt(8081)).
+							Build(),
+					).
+					KV2(
+						"defaults",
+						testlinkedast.NewObjectBuilder().
+							KV2("port", linkedast.NewInt(8080)).
+							KV2("timeout", linkedast.NewDuration(5e9)).
+							Build(),
+					).
+					KV2("timeout", linkedast.NewDuration(5e9)).
+					KV2(
+						"replicas",
+						testlinkedast.NewArrayBuilder().
+							Element(
+								testlinkedast.NewObjectBuilder().
+									KV2("name", linkedast.NewString("a")).
+									KV2("url", linkedast.NewString("http://a:8081")).
+									Build(),
+							).
+							Build(),
+					).
+					KV2(
+						"base",
+						testlinkedast.NewObjectBuilder().
+							KV2("name", linkedast.NewString("base")).
+							KV2("title", linkedast.NewString("BASE")).
+							Build(),
+					)
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	a: self.b + 1
+	b: root.a * 2
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrCyclicReference)
+		require.ErrorContains(t, err, "root.a -> root.b -> root.a at /home/user/config.atmc:2:1")
+	})
+
+	t.Run("containing_object", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	server: {
+		copy: root.server
+	}
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, linker.ErrCyclicReference)
+		require.ErrorContains(t, err, "root.server -> root.server.copy -> root.server at /home/user/config.atmc:2:1")
+	})
+
+	t.Run("undefined_key", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	server: {
+		host: "localhost"
+		url: self.hots
+	}
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, analyzer.ErrUndefinedKey)
+		require.ErrorContains(t, err, "self.hots at 4:7")
+	})
+
+	t.Run("reserved_name", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`self = 1
+
+{value: self}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, analyzer.ErrReservedVariable)
+	})
+}
@@ -0,0 +1,172 @@
+package acceptance
+
+import (
+	"testing"
+
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_SpreadMerge(t *testing.T) {
+	t.Parallel()
+
+	commonFile := func(fb *testos.FileBuilder) {
+		fb.
+			Path("/home/user/common.atmc").
+			Content(`
+{
+	logging: {
+		level: "info"
+		format: "json"
+	}
+	port: 8080
+}
+`)
+	}
+
+	overridesFile := func(fb *testos.FileBuilder) {
+		fb.
+			Path("/home/user/overrides.atmc").
+			Content(`
+{
+	logging: {
+		level: "warn"
+		output: "stderr"
+	}
+}
+`)
+	}
+
+	logging := func(level, format string, extra ...string) linkedast.Object {
+		ob := testlinkedast.NewObjectBuilder().
+			KV2("level", linkedast.NewString(level)).
+			KV2("format", linkedast.NewString(format))
+		for i := 0; i < len(extra); i += 2 {
+			ob.KV2(extra[i], linkedast.NewString(extra[i+1]))
+		}
+
+		return ob.Build()
+	}
+
+	testCases := []struct {
+		name     string
+		content  string
+		expected func(ob *testlinkedast.ObjectBuilder)
+	}{
+		{
+			name: "spread_after_kv",
+			content: `
+common ./common.atmc
+
+{
+	logging: {
+		level: "debug"
+		color: "auto"
+	}
+	common...
+}
+`,
+			expected: func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2(
+						"logging",
+						testlinkedast.NewObjectBuilder().
+							KV2("level", linkedast.NewString("info")).
+							KV2("color", linkedast.NewString("auto")).
+							KV2("format", linkedast.NewString("json")).
+							Build(),
+					).
+					KV2("port", linkedast.NewInt(8080))
+			},
+		},
+		{
+			name: "kv_after_spread",
+			content: `
+common ./common.atmc
+
+{
+	common...
+	logging: {
+		level: "debug"
+	}
+}
+`,
+			expected: func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("logging", logging("debug", "json")).
+					KV2("port", linkedast.NewInt(8080))
+			},
+		},
+		{
+			name: "spread_after_spread",
+			content: `
+common ./common.atmc
+overrides ./overrides.atmc
+
+{
+	common...
+	overrides...
+}
+`,
+			expected: func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("logging", logging("warn", "json", "output", "stderr")).
+					KV2("port", linkedast.NewInt(8080))
+			},
+		},
+		{
+			name: "nested_spreads",
+			content: `
+common ./common.atmc
+overrides ./overrides.atmc
+
+{
+	service: {
+		common...
+		logging: {
+			overrides.logging...
+			format: "text"
+		}
+	}
+}
+`,
+			expected: func(ob *testlinkedast.ObjectBuilder) {
+				ob.KV2(
+					"service",
+					testlinkedast.NewObjectBuilder().
+						KV2("logging", logging("warn", "text", "output", "stderr")).
+						KV2("port", linkedast.NewInt(8080)).
+						Build(),
+				)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mainFilePath := "/home/user/config.atmc"
+
+			os := testos.NewOSBuilder().
+				File(func(fb *testos.FileBuilder) {
+					fb.
+						Path(mainFilePath).
+						Content(tc.content)
+				}).
+				File(commonFile).
+				File(overridesFile).
+				Build()
+
+			app := test.NewApp(t, test.WithOS(os))
+
+			a, err := app.Processor().Process(mainFilePath)
+			require.NoError(t, err)
+
+			require.Equal(t, testlinkedast.NewBuilder().Object(tc.expected).Build(), a)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package acceptance
+
+import (
+	"testing"
+
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_Strings(t *testing.T) {
+	t.Parallel()
+
+	t.Run("raw_and_multiline_strings", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+{
+	tls: {
+		cert: """
+			-----BEGIN CERTIFICATE-----
+			MIIBszCCAVmgAwIBAgIU
+			-----END CERTIFICATE-----
+
+			"""
+	}
+	query: ` + "`SELECT * FROM users WHERE name LIKE '%\\_%'`" + `
+	escaped: "tab:\t"
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2(
+						"tls",
+						testlinkedast.NewObjectBuilder().
+							KV2("cert", linkedast.NewString(
+								"-----BEGIN CERTIFICATE-----\nMIIBszCCAVmgAwIBAgIU\n-----END CERTIFICATE-----\n",
+							)).
+							Build(),
+					).
+					KV2("query", linkedast.NewString(`SELECT * FROM users WHERE name LIKE '%\_%'`)).
+					KV2("escaped", linkedast.NewString("tab:\t"))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+}
@@ -0,0 +1,90 @@
+package acceptance
+
+import (
+	"testing"
+	"time"
+
+	linkedast "github.com/atmxlab/atmc/linker/ast"
+	parserast "github.com/atmxlab/atmc/parser/ast"
+	"github.com/atmxlab/atmc/test"
+	"github.com/atmxlab/atmc/test/testlinkedast"
+	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessor_Units(t *testing.T) {
+	t.Parallel()
+
+	t.Run("durations_and_byte_sizes", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`
+{
+	timeout: 1m30s
+	poll: 250ms
+	backoff: [100ms 1s 1.5m]
+	buffer: 512MiB
+	disk: 10GB
+	deadline: 1_500ms
+	archive: 1_024MiB
+}
+`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		a, err := app.Processor().Process(mainFilePath)
+		require.NoError(t, err)
+
+		expectedAst := testlinkedast.NewBuilder().
+			Object(func(ob *testlinkedast.ObjectBuilder) {
+				ob.
+					KV2("timeout", linkedast.NewDuration(90*time.Second)).
+					KV2("poll", linkedast.NewDuration(250*time.Millisecond)).
+					KV2(
+						"backoff",
+						testlinkedast.NewArrayBuilder().
+							Element(linkedast.NewDuration(100*time.Millisecond)).
+							Element(linkedast.NewDuration(time.Second)).
+							Element(linkedast.NewDuration(90*time.Second)).
+							Build(),
+					).
+					KV2("buffer", linkedast.NewByteSize(512<<20)).
+					KV2("disk", linkedast.NewByteSize(10_000_000_000)).
+					KV2("deadline", linkedast.NewDuration(1500*time.Millisecond)).
+					KV2("archive", linkedast.NewByteSize(1024<<20))
+			}).
+			Build()
+
+		require.Equal(t, expectedAst, a)
+	})
+
+	t.Run("fractional_bytes", func(t *testing.T) {
+		t.Parallel()
+
+		mainFilePath := "/home/user/config.atmc"
+
+		os := testos.NewOSBuilder().
+			File(func(fb *testos.FileBuilder) {
+				fb.
+					Path(mainFilePath).
+					Content(`{
+	size: 1.5B
+}`)
+			}).
+			Build()
+
+		app := test.NewApp(t, test.WithOS(os))
+
+		_, err := app.Processor().Process(mainFilePath)
+		require.ErrorIs(t, err, parserast.ErrInvalidNumber)
+		require.ErrorContains(t, err, "at 2:7")
+	})
+}
@@ -9,10 +9,12 @@ import (
 	"github.com/atmxlab/atmc/parser"
 	"github.com/atmxlab/atmc/processor"
 	"github.com/atmxlab/atmc/test/testos"
+	"github.com/stretchr/testify/require"
 )
 
 type config struct {
-	os testos.OS
+	os        testos.OS
+	functions []linker.Option
 }
 
 func newConfig() *config {
@@ -29,6 +31,16 @@ func WithOS(os testos.OS) ConfigOpt {
 	}
 }
 
+// WithFunction регистрирует пользовательскую функцию. Неподходящая сигнатура роняет тест.
+func WithFunction(t *testing.T, name string, fn any) ConfigOpt {
+	function, err := linker.NewFunction(fn)
+	require.NoError(t, err)
+
+	return func(c *config) {
+		c.functions = append(c.functions, linker.WithFunction(name, function))
+	}
+}
+
 type App struct {
 	t         *testing.T
 	processor *processor.Processor
@@ -40,11 +52,13 @@ func NewApp(t *testing.T, opts ...ConfigOpt) *App {
 		opt(cfg)
 	}
 
+	lnk := linker.New(cfg.functions...)
+
 	p := processor.New(
 		lexer.New(),
 		parser.New(),
-		analyzer.New(),
-		linker.New(),
+		analyzer.New(analyzer.WithFunctions(lnk.FunctionNames()...)),
+		lnk,
 		cfg.os,
 	)
 
@@ -5,22 +5,28 @@ import (
 )
 
 type Builder struct {
-	obj ast.Object
+	root ast.Expression
 }
 
 func NewBuilder() *Builder {
-	return &Builder{}
+	return &Builder{root: ast.Object{}}
 }
 
 func (b *Builder) Object(hook func(ob *ObjectBuilder)) *Builder {
 	ob := NewObjectBuilder()
 	hook(ob)
-	b.obj = ob.Build()
+	b.root = ob.Build()
+	return b
+}
+
+// Root задает корень документа, который не является объектом.
+func (b *Builder) Root(root ast.Expression) *Builder {
+	b.root = root
 	return b
 }
 
 func (b *Builder) Build() ast.Ast {
-	return ast.NewAst(b.obj)
+	return ast.NewAst(b.root)
 }
 
 type ObjectBuilder struct {
@@ -36,6 +42,11 @@ func (b *ObjectBuilder) KV2(key string, value ast.Expression) *ObjectBuilder {
 	return b
 }
 
+func (b *ObjectBuilder) DocKV(doc string, key string, value ast.Expression) *ObjectBuilder {
+	b.kv = append(b.kv, ast.NewKV(ast.NewIdent(key), value).SetDoc(doc))
+	return b
+}
+
 func (b *ObjectBuilder) Build() ast.Object {
 	return ast.NewObject(b.kv)
 }
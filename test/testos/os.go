@@ -2,6 +2,7 @@ package testos
 
 import (
 	"path/filepath"
+	"sort"
 
 	"github.com/atmxlab/atmc/pkg/errors"
 )
@@ -31,6 +32,25 @@ func (o OS) AbsPath(baseDir, relPath string) (string, error) {
 	return filepath.Join(baseDir, relPath), nil
 }
 
+func (o OS) Glob(pattern string) ([]string, error) {
+	matches := make([]string, 0)
+
+	for path := range o.contentByFile {
+		ok, err := filepath.Match(pattern, path)
+		if err != nil {
+			return nil, errors.Wrap(err, "filepath.Match")
+		}
+
+		if ok {
+			matches = append(matches, path)
+		}
+	}
+
+	sort.Strings(matches)
+
+	return matches, nil
+}
+
 type OSBuilder struct {
 	contentByFile map[string][]byte
 	env           map[string]string
@@ -72,6 +72,11 @@ func (p Position) IncrLine() Position {
 	return p
 }
 
+func (p Position) AddLine(amount uint) Position {
+	p.line += amount
+	return p
+}
+
 func (p Position) Column() uint {
 	return p.column
 }
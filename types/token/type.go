@@ -36,6 +36,78 @@ func (t Type) String() string {
 		return "float"
 	case String:
 		return "string"
+	case RawString:
+		return "raw string"
+	case MultilineString:
+		return "multiline string"
+	case BlockComment:
+		return "block comment"
+	case DocComment:
+		return "doc comment"
+	case Null:
+		return "null"
+	case Duration:
+		return "duration"
+	case ByteSize:
+		return "byte size"
+	case TemplateStart:
+		return "template start"
+	case TemplateEnd:
+		return "template end"
+	case Interpolation:
+		return "interpolation"
+	case Coalesce:
+		return "coalesce"
+	case Bang:
+		return "bang"
+	case Plus:
+		return "plus"
+	case Minus:
+		return "minus"
+	case Star:
+		return "star"
+	case Slash:
+		return "slash"
+	case Percent:
+		return "percent"
+	case Assign:
+		return "assign"
+	case Eq:
+		return "equal"
+	case NotEq:
+		return "not equal"
+	case Lt:
+		return "less"
+	case LtEq:
+		return "less or equal"
+	case Gt:
+		return "greater"
+	case GtEq:
+		return "greater or equal"
+	case And:
+		return "and"
+	case Or:
+		return "or"
+	case LParen:
+		return "left paren"
+	case RParen:
+		return "right paren"
+	case If:
+		return "if"
+	case Then:
+		return "then"
+	case Else:
+		return "else"
+	case Delete:
+		return "delete"
+	case AppendColon:
+		return "append colon"
+	case PrependColon:
+		return "prepend colon"
+	case ReplaceColon:
+		return "replace colon"
+	case At:
+		return "at"
 	case Bool:
 		return "bool"
 	case Ident:
@@ -68,6 +140,44 @@ const (
 	Path
 	Dot
 	Comment
+	RawString
+	MultilineString
+	BlockComment
+	DocComment
+	Null
+	Duration
+	ByteSize
+	// TemplateStart и TemplateEnd обрамляют строку с интерполяцией, Interpolation - открывающий "${".
+	// Лексер формирует их сам при разборе String, регулярных выражений у них нет.
+	TemplateStart
+	TemplateEnd
+	Interpolation
+	Coalesce
+	Bang
+	Plus
+	Minus
+	Star
+	Slash
+	Percent
+	Eq
+	NotEq
+	Lt
+	LtEq
+	Gt
+	GtEq
+	And
+	Or
+	LParen
+	RParen
+	If
+	Then
+	Else
+	Assign
+	Delete
+	AppendColon
+	PrependColon
+	ReplaceColon
+	At
 )
 
 var typeRegexps = map[Type]*regexp.Regexp{
@@ -81,14 +191,71 @@ var typeRegexps = map[Type]*regexp.Regexp{
 	Comma:    regexp.MustCompile("^,"),
 	Dot:      regexp.MustCompile("^\\."),
 	Colon:    regexp.MustCompile("^:"),
-	Int:      regexp.MustCompile("^[-+]?[0-9]+"),
-	Float:    regexp.MustCompile("^[-+]?[0-9]+(\\.[0-9]+)"),
+	Int:      regexp.MustCompile(`^[-+]?(?:0[xX](?:_?[0-9a-fA-F])+|0[oO](?:_?[0-7])+|0[bB](?:_?[01])+|[0-9](?:_?[0-9])*)`),
+	Float:    regexp.MustCompile(`^[-+]?(?:(?:[0-9](?:_?[0-9])*)?\.[0-9](?:_?[0-9])*(?:[eE][-+]?[0-9](?:_?[0-9])*)?|[0-9](?:_?[0-9])*[eE][-+]?[0-9](?:_?[0-9])*)`),
 	Bool:     regexp.MustCompile("^(true|false)\\b"),
-	String:   regexp.MustCompile(`^"(?:[^\\"]|\\.|\\\\)*"`),
+	String:   regexp.MustCompile(`^"(?:[^\\"$]|\\.|\\\\|\$\{[^}]*}|\$)*"`),
 	Ident:    regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*"),
-	Path:     regexp.MustCompile("^(?:/|\\./)[a-zA-Z0-9._/-]+"),
+	Path:     regexp.MustCompile("^\\??(?:/[a-zA-Z0-9._-][a-zA-Z0-9._/*-]*|\\./[a-zA-Z0-9._/*-]+)"),
 	Dollar:   regexp.MustCompile("^\\$"),
 	Comment:  regexp.MustCompile(`^//.*`),
+
+	// Содержимое raw строки не обрабатывается: escape-последовательностей нет, переносы сохраняются.
+	RawString: regexp.MustCompile("^`[^`]*`"),
+	// Многострочная строка: общий отступ строк убирается, escape-последовательности обрабатываются.
+	MultilineString: regexp.MustCompile(`^"""(?:[^\\]|\\[\s\S])*?"""`),
+
+	BlockComment: regexp.MustCompile(`^/\*[\s\S]*?\*/`),
+	// Документирующий комментарий привязывается к следующему за ним ключу.
+	// Четыре и более слеша - обычный комментарий.
+	DocComment: regexp.MustCompile(`^///(?:[^/\n].*)?(?m:$)`),
+
+	Null: regexp.MustCompile("^null\\b"),
+
+	// Длительность в формате time.ParseDuration: 250ms, 1m30s, 1.5h. Разделители "_" как у чисел: 1_000ms.
+	Duration: regexp.MustCompile(`^[-+]?(?:[0-9](?:_?[0-9])*(?:\.[0-9](?:_?[0-9])*)?(?:ns|us|µs|ms|s|m|h))+\b`),
+	// Размер в байтах: SI (kB, MB, ...) - степени 1000, IEC (KiB, MiB, ...) - степени 1024.
+	ByteSize: regexp.MustCompile(`^[0-9](?:_?[0-9])*(?:\.[0-9](?:_?[0-9])*)?(?:[KMGTPE]iB|[kKMGTPE]B|B)\b`),
+
+	// Значение по умолчанию для переменной среды: $PORT ?? 8080.
+	Coalesce: regexp.MustCompile(`^\?\?`),
+	// Маркер обязательной переменной среды: $DB_PASSWORD!, а также логическое отрицание.
+	Bang: regexp.MustCompile(`^!`),
+
+	Plus:    regexp.MustCompile(`^\+`),
+	Minus:   regexp.MustCompile(`^-`),
+	Star:    regexp.MustCompile(`^\*`),
+	Slash:   regexp.MustCompile(`^/`),
+	Percent: regexp.MustCompile(`^%`),
+	Eq:      regexp.MustCompile(`^==`),
+	NotEq:   regexp.MustCompile(`^!=`),
+	Lt:      regexp.MustCompile(`^<`),
+	LtEq:    regexp.MustCompile(`^<=`),
+	Gt:      regexp.MustCompile(`^>`),
+	GtEq:    regexp.MustCompile(`^>=`),
+	And:     regexp.MustCompile(`^&&`),
+	Or:      regexp.MustCompile(`^\|\|`),
+	LParen:  regexp.MustCompile(`^\(`),
+	RParen:  regexp.MustCompile(`^\)`),
+
+	// Условное выражение: if $STAGE == "prod" then 5 else 1.
+	If:   regexp.MustCompile(`^if\b`),
+	Then: regexp.MustCompile(`^then\b`),
+	Else: regexp.MustCompile(`^else\b`),
+
+	// Маркер удаления ключа при слиянии: tracing: delete.
+	Delete: regexp.MustCompile(`^delete\b`),
+
+	// Стратегии слияния ключа: level +: ["debug"], brokers ^: [...], logging =: {...}.
+	AppendColon:  regexp.MustCompile(`^\+:`),
+	PrependColon: regexp.MustCompile(`^\^:`),
+	ReplaceColon: regexp.MustCompile(`^=:`),
+
+	// Аннотация ключа: services @merge(name): [...].
+	At: regexp.MustCompile(`^@`),
+
+	// Локальная переменная файла: host = "db.internal".
+	Assign: regexp.MustCompile(`^=`),
 }
 
 func (t Type) Regexp() *regexp.Regexp {
@@ -103,7 +270,13 @@ func (t Type) Regexp() *regexp.Regexp {
 func (t Type) Postprocess(v string) string {
 	switch t {
 	case String:
-		return strings.Trim(v, "\"")
+		return strings.TrimSuffix(strings.TrimPrefix(v, `"`), `"`)
+	case RawString:
+		return strings.TrimSuffix(strings.TrimPrefix(v, "`"), "`")
+	case MultilineString:
+		return strings.TrimSuffix(strings.TrimPrefix(v, `"""`), `"""`)
+	case DocComment:
+		return strings.TrimRight(strings.TrimPrefix(strings.TrimPrefix(v, "///"), " "), "\r")
 	default:
 		return v
 	}
@@ -113,10 +286,21 @@ func OrderedTokenTypes() []Type {
 	return []Type{
 		WS,
 		EOL,
+		DocComment,
+		BlockComment,
 		Comment,
+		MultilineString,
 		String,
+		RawString,
 		Path,
 		Bool,
+		Null,
+		If,
+		Then,
+		Else,
+		Delete,
+		Duration,
+		ByteSize,
 		Float,
 		Int,
 		LBrace,
@@ -127,7 +311,29 @@ func OrderedTokenTypes() []Type {
 		Comma,
 		Dot,
 		Dollar,
+		At,
+		AppendColon,
+		PrependColon,
+		ReplaceColon,
 		Colon,
+		Coalesce,
+		Eq,
+		NotEq,
+		LtEq,
+		Lt,
+		GtEq,
+		Gt,
+		Assign,
+		And,
+		Or,
+		Plus,
+		Minus,
+		Star,
+		Slash,
+		Percent,
+		LParen,
+		RParen,
+		Bang,
 		Ident,
 	}
 }
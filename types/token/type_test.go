@@ -386,6 +386,31 @@ func TestType_Int_Regexp(t *testing.T) {
 			input:    `-123::::...]test{}[][]231...:sda2131from||||import {test: 123}`,
 			expected: []int{0, 4},
 		},
+		{
+			name:     "hex",
+			input:    `0x1F_ff key`,
+			expected: []int{0, 7},
+		},
+		{
+			name:     "octal",
+			input:    `0o755 key`,
+			expected: []int{0, 5},
+		},
+		{
+			name:     "binary",
+			input:    `-0b1010 key`,
+			expected: []int{0, 7},
+		},
+		{
+			name:     "underscores",
+			input:    `1_000_000 key`,
+			expected: []int{0, 9},
+		},
+		{
+			name:     "double underscore",
+			input:    `1__000 key`,
+			expected: []int{0, 1},
+		},
 		{
 			name:     "not start with",
 			input:    `[:...[][][test{}[][]231:::sda2131from||||import...`,
@@ -426,6 +451,31 @@ func TestType_Float_Regexp(t *testing.T) {
 			input:    `-123.123::::...]test{}[][]231...:sda2131from||||import {test: 123}`,
 			expected: []int{0, 8},
 		},
+		{
+			name:     "without integer part",
+			input:    `.5 key`,
+			expected: []int{0, 2},
+		},
+		{
+			name:     "exponent",
+			input:    `1e-9 key`,
+			expected: []int{0, 4},
+		},
+		{
+			name:     "fraction and exponent",
+			input:    `-1_000.5E+10 key`,
+			expected: []int{0, 12},
+		},
+		{
+			name:     "hex int",
+			input:    `0x1e5`,
+			expected: nil,
+		},
+		{
+			name:     "spread",
+			input:    `...`,
+			expected: nil,
+		},
 		{
 			name:     "not start with",
 			input:    `[:...[][][test{}[][]231:::sda2131from||||import... 123.123`,
@@ -521,6 +571,21 @@ func TestType_String_Regexp(t *testing.T) {
 			input:    `""::::...]test{}[][]231...:sda2131from||||import 123.123`,
 			expected: []int{0, 2},
 		},
+		{
+			name:     "start with interpolation",
+			input:    `"a ${db.user} b" key: 1`,
+			expected: []int{0, 16},
+		},
+		{
+			name:     "start with quoted key in interpolation",
+			input:    `"${db."x-user"}" key: 1`,
+			expected: []int{0, 16},
+		},
+		{
+			name:     "start with dollar",
+			input:    `"$5 and ${" key: 1`,
+			expected: []int{0, 11},
+		},
 		{
 			name:     "start not with",
 			input:    `::::...]test{}[][]231...:sda2131from"test"||||import 123.123`,
@@ -627,3 +692,365 @@ func TestType_Path_Regexp(t *testing.T) {
 		})
 	}
 }
+
+func TestType_RawString_Regexp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected []int
+	}{
+		{
+			name:     "start with",
+			input:    "`raw \\n string` true 123",
+			expected: []int{0, 15},
+		},
+		{
+			name:     "multiline",
+			input:    "`line1\nline2`::::",
+			expected: []int{0, 13},
+		},
+		{
+			name:     "not start with",
+			input:    "key: `raw`",
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			indexes := token.RawString.Regexp().FindStringIndex(tc.input)
+			require.Equal(t, tc.expected, indexes)
+		})
+	}
+}
+
+func TestType_MultilineString_Regexp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected []int
+	}{
+		{
+			name:     "start with",
+			input:    "\"\"\"\n  line1\n  line2\n\"\"\" key: \"\"\"value\"\"\"",
+			expected: []int{0, 23},
+		},
+		{
+			name:     "with escaped quotes",
+			input:    `"""a \""" b""" c`,
+			expected: []int{0, 14},
+		},
+		{
+			name:     "simple string",
+			input:    `"test string"`,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			indexes := token.MultilineString.Regexp().FindStringIndex(tc.input)
+			require.Equal(t, tc.expected, indexes)
+		})
+	}
+}
+
+func TestType_Null_Regexp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected []int
+	}{
+		{
+			name:     "start with",
+			input:    `null 123 key: true`,
+			expected: []int{0, 4},
+		},
+		{
+			name:     "start with ident",
+			input:    `nullable: true`,
+			expected: nil,
+		},
+		{
+			name:     "not start with",
+			input:    `key: null`,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			indexes := token.Null.Regexp().FindStringIndex(tc.input)
+			require.Equal(t, tc.expected, indexes)
+		})
+	}
+}
+
+func TestType_Keywords_Regexp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		t        token.Type
+		input    string
+		expected []int
+	}{
+		{name: "if", t: token.If, input: `if $STAGE == "prod"`, expected: []int{0, 2}},
+		{name: "if with ident", t: token.If, input: `iface: 1`, expected: nil},
+		{name: "then", t: token.Then, input: `then 5 else 1`, expected: []int{0, 4}},
+		{name: "then with ident", t: token.Then, input: `thenable: 1`, expected: nil},
+		{name: "else", t: token.Else, input: `else 1`, expected: []int{0, 4}},
+		{name: "else with ident", t: token.Else, input: `elsewhere: 1`, expected: nil},
+		{name: "not start with", t: token.Else, input: `5 else 1`, expected: nil},
+		{name: "delete", t: token.Delete, input: `delete }`, expected: []int{0, 6}},
+		{name: "delete with ident", t: token.Delete, input: `deleted: true`, expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			indexes := tc.t.Regexp().FindStringIndex(tc.input)
+			require.Equal(t, tc.expected, indexes)
+		})
+	}
+}
+
+func TestType_Duration_Regexp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected []int
+	}{
+		{
+			name:     "milliseconds",
+			input:    `250ms key: 1`,
+			expected: []int{0, 5},
+		},
+		{
+			name:     "compound",
+			input:    `1h30m15s]`,
+			expected: []int{0, 8},
+		},
+		{
+			name:     "fractional",
+			input:    `1.5h`,
+			expected: []int{0, 4},
+		},
+		{
+			name:     "negative",
+			input:    `-10s`,
+			expected: []int{0, 4},
+		},
+		{
+			name:     "micro sign",
+			input:    `10µs`,
+			expected: []int{0, 5},
+		},
+		{
+			name:     "without unit",
+			input:    `1500`,
+			expected: nil,
+		},
+		{
+			name:     "unknown unit",
+			input:    `5min`,
+			expected: nil,
+		},
+		{
+			name:     "not start with",
+			input:    `key: 1s`,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			indexes := token.Duration.Regexp().FindStringIndex(tc.input)
+			require.Equal(t, tc.expected, indexes)
+		})
+	}
+}
+
+func TestType_ByteSize_Regexp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected []int
+	}{
+		{
+			name:     "iec",
+			input:    `512MiB key: 1`,
+			expected: []int{0, 6},
+		},
+		{
+			name:     "si",
+			input:    `10GB]`,
+			expected: []int{0, 4},
+		},
+		{
+			name:     "kilobytes",
+			input:    `64kB`,
+			expected: []int{0, 4},
+		},
+		{
+			name:     "bytes",
+			input:    `100B`,
+			expected: []int{0, 4},
+		},
+		{
+			name:     "fractional",
+			input:    `1.5KiB`,
+			expected: []int{0, 6},
+		},
+		{
+			name:     "unknown unit",
+			input:    `10GiBs`,
+			expected: nil,
+		},
+		{
+			name:     "negative",
+			input:    `-1MB`,
+			expected: nil,
+		},
+		{
+			name:     "not start with",
+			input:    `key: 1MB`,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			indexes := token.ByteSize.Regexp().FindStringIndex(tc.input)
+			require.Equal(t, tc.expected, indexes)
+		})
+	}
+}
+
+func TestType_Coalesce_Regexp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected []int
+	}{
+		{
+			name:     "start with",
+			input:    `?? 8080`,
+			expected: []int{0, 2},
+		},
+		{
+			name:     "single question mark",
+			input:    `? 8080`,
+			expected: nil,
+		},
+		{
+			name:     "not start with",
+			input:    `$PORT ?? 8080`,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			indexes := token.Coalesce.Regexp().FindStringIndex(tc.input)
+			require.Equal(t, tc.expected, indexes)
+		})
+	}
+}
+
+func TestType_Bang_Regexp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected []int
+	}{
+		{
+			name:     "start with",
+			input:    `! key: 1`,
+			expected: []int{0, 1},
+		},
+		{
+			name:     "not start with",
+			input:    `$DB_PASSWORD!`,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			indexes := token.Bang.Regexp().FindStringIndex(tc.input)
+			require.Equal(t, tc.expected, indexes)
+		})
+	}
+}
+
+func TestType_Operators_Regexp(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		t        token.Type
+		input    string
+		expected []int
+	}{
+		{name: "plus", t: token.Plus, input: `+ 1`, expected: []int{0, 1}},
+		{name: "minus", t: token.Minus, input: `- 1`, expected: []int{0, 1}},
+		{name: "star", t: token.Star, input: `* 2`, expected: []int{0, 1}},
+		{name: "slash", t: token.Slash, input: `/ 2`, expected: []int{0, 1}},
+		{name: "percent", t: token.Percent, input: `% 2`, expected: []int{0, 1}},
+		{name: "equal", t: token.Eq, input: `== 2`, expected: []int{0, 2}},
+		{name: "not equal", t: token.NotEq, input: `!= 2`, expected: []int{0, 2}},
+		{name: "less", t: token.Lt, input: `< 2`, expected: []int{0, 1}},
+		{name: "less or equal", t: token.LtEq, input: `<= 2`, expected: []int{0, 2}},
+		{name: "greater", t: token.Gt, input: `> 2`, expected: []int{0, 1}},
+		{name: "greater or equal", t: token.GtEq, input: `>= 2`, expected: []int{0, 2}},
+		{name: "and", t: token.And, input: `&& x`, expected: []int{0, 2}},
+		{name: "single ampersand", t: token.And, input: `& x`, expected: nil},
+		{name: "or", t: token.Or, input: `|| x`, expected: []int{0, 2}},
+		{name: "left paren", t: token.LParen, input: `(x)`, expected: []int{0, 1}},
+		{name: "right paren", t: token.RParen, input: `) x`, expected: []int{0, 1}},
+		{name: "append colon", t: token.AppendColon, input: `+: [1]`, expected: []int{0, 2}},
+		{name: "prepend colon", t: token.PrependColon, input: `^: [1]`, expected: []int{0, 2}},
+		{name: "replace colon", t: token.ReplaceColon, input: `=: [1]`, expected: []int{0, 2}},
+		{name: "at", t: token.At, input: `@merge(name)`, expected: []int{0, 1}},
+		{name: "not start with", t: token.Plus, input: `1 + 2`, expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			indexes := tc.t.Regexp().FindStringIndex(tc.input)
+			require.Equal(t, tc.expected, indexes)
+		})
+	}
+}